@@ -16,6 +16,9 @@ var ErrNotVerified = fmt.Errorf("artifact is not verified")
 // ErrNotVerified is returned when an artifact is not found on CNLC
 var ErrNotFound = fmt.Errorf("artifact is not found")
 
+// ErrAttachmentLabelNotFound is returned when no attachment is found on CNLC for a given label
+var ErrAttachmentLabelNotFound = fmt.Errorf("provided label does not contains entries")
+
 // Error represents a CodeNotary platform's API returned error.
 type Error struct {
 	Description string   `json:"description"`
@@ -129,16 +129,58 @@ type LcArtifact struct {
 	Metadata    Metadata     `json:"metadata" yaml:"metadata" vcn:"Metadata"`
 	Attachments []Attachment `json:"attachments" yaml:"attachments" vcn:"Attachments"`
 
+	// validity window, reserved fields set by notarization
+	ValidFrom  *time.Time `json:"validFrom,omitempty" yaml:"validFrom,omitempty" vcn:"Valid from"`
+	ValidUntil *time.Time `json:"validUntil,omitempty" yaml:"validUntil,omitempty" vcn:"Valid until"`
+
 	Signer  string      `json:"signer" yaml:"signer" vcn:"SignerID"`
 	Revoked *time.Time  `json:"revoked,omitempty" yaml:"revoked" vcn:"Apikey revoked"`
 	Status  meta.Status `json:"status" yaml:"status" vcn:"Status"`
 	Ledger  string      `json:"ledger,omitempty" yaml:"ledger"`
 }
 
-func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []string) (bool, uint64, error) {
+// Valid returns true if t is within the validity window of the notarization, if any.
+// The window includes ValidFrom and excludes ValidUntil.
+func (lca *LcArtifact) Valid(t time.Time) bool {
+	if lca.ValidFrom != nil && t.Before(*lca.ValidFrom) {
+		return false
+	}
+	if lca.ValidUntil != nil && !t.Before(*lca.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+func (u LcUser) createArtifact(artifact Artifact, o *lcSignOpts) (bool, uint64, error) {
+	kvs, err := u.artifactKVs(artifact, o)
+	if err != nil {
+		return false, 0, err
+	}
+
+	txMeta, err := u.Client.SetAll(lcNotarizeContext(), &immuschema.SetRequest{KVs: kvs})
+	if err != nil {
+		return false, 0, err
+	}
+	return true, txMeta.Id, nil
+}
+
+// lcNotarizeContext returns the context for notarization requests.
+func lcNotarizeContext() context.Context {
+	md := metadata.Pairs(
+		meta.VcnLCPluginTypeHeaderName, meta.VcnLCPluginTypeHeaderValue,
+		meta.VcnLCCmdHeaderName, meta.VcnLCNotarizeCmdHeaderValue,
+	)
+	return metadata.NewOutgoingContext(context.Background(), md)
+}
+
+// artifactKVs returns the entries that notarize the artifact, i.e. the artifact itself
+// followed by its attachments and attachments' labels, if any.
+func (u LcUser) artifactKVs(artifact Artifact, o *lcSignOpts) ([]*immuschema.KeyValue, error) {
 
 	aR := artifact.toLcArtifact()
-	aR.Status = status
+	aR.Status = o.status
+	aR.ValidFrom = o.validFrom
+	aR.ValidUntil = o.validUntil
 
 	aR.Signer = GetSignerIDByApiKey(u.Client.ApiKey)
 
@@ -154,7 +196,7 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 
 	// map to save all the attachments with a specific label
 	labelMap := make(map[string][]Attachment)
-	for _, al := range attach {
+	for _, al := range o.attach {
 		// attachment can be --attach=vscanner.result:jobid123. jobid123 is the label
 		alSlice := strings.SplitN(al, ":", 2)
 		a := alSlice[0]
@@ -167,17 +209,17 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 		// attachment
 		f, err := os.Open(a)
 		if err != nil {
-			return false, 0, err
+			return nil, err
 		}
 		defer f.Close()
 
 		fc, err := ioutil.ReadFile(a)
 		if err != nil {
-			return false, 0, err
+			return nil, err
 		}
 		h := sha256.New()
 		if _, err := io.Copy(h, f); err != nil {
-			return false, 0, err
+			return nil, err
 		}
 		checksum := h.Sum(nil)
 		hash := hex.EncodeToString(checksum)
@@ -205,7 +247,7 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 			attachs := []Attachment{at}
 			attachmentsListJson, err := json.Marshal(attachs)
 			if err != nil {
-				return false, 0, err
+				return nil, err
 			}
 			labelKV := &immuschema.KeyValue{
 				Key:   []byte(labelKey),
@@ -224,16 +266,9 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 	aR.Attachments = aRattachment
 	arJson, err := json.Marshal(aR)
 	if err != nil {
-		return false, 0, err
+		return nil, err
 	}
 
-	md := metadata.Pairs(
-		meta.VcnLCPluginTypeHeaderName, meta.VcnLCPluginTypeHeaderValue,
-		meta.VcnLCCmdHeaderName, meta.VcnLCNotarizeCmdHeaderValue,
-	)
-	ctx := metadata.NewOutgoingContext(context.Background(), md)
-
-	var txMeta *immuschema.TxMetadata
 	eor := &immuschema.SetRequest{
 		KVs: []*immuschema.KeyValue{
 			{
@@ -253,7 +288,7 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 
 		attachmentsListJson, err := json.Marshal(attachments)
 		if err != nil {
-			return false, 0, err
+			return nil, err
 		}
 		labelMapKV := &immuschema.KeyValue{
 			Key:   []byte(labelMapKey),
@@ -262,11 +297,7 @@ func (u LcUser) createArtifact(artifact Artifact, status meta.Status, attach []s
 
 		eor.KVs = append(eor.KVs, labelMapKV)
 	}
-	txMeta, err = u.Client.SetAll(ctx, eor)
-	if err != nil {
-		return false, 0, err
-	}
-	return true, txMeta.Id, nil
+	return eor.KVs, nil
 }
 
 // LoadArtifact fetches and returns an *lcArtifact for the given hash and current u, if any.
@@ -339,6 +370,33 @@ func (u *LcUser) LoadArtifact(
 	return lcArtifact, true, nil
 }
 
+// TrustedSigners returns the IDs, among signerIDs, of the signers that notarized the given hash
+// with a verified, trusted, not revoked and not expired notarization. Duplicated IDs are counted once.
+func (u *LcUser) TrustedSigners(hash string, signerIDs []string, gRPCMetadata map[string][]string) ([]string, error) {
+	trusted := []string{}
+	seen := make(map[string]bool, len(signerIDs))
+	for _, signerID := range signerIDs {
+		if signerID == "" || seen[signerID] {
+			continue
+		}
+		seen[signerID] = true
+
+		ar, verified, err := u.LoadArtifact(hash, signerID, "", 0, gRPCMetadata)
+		switch err {
+		case nil:
+		case ErrNotFound, ErrNotVerified:
+			continue
+		default:
+			return nil, err
+		}
+		if !verified || ar.Status != meta.StatusTrusted || (ar.Revoked != nil && !ar.Revoked.IsZero()) || !ar.Valid(time.Now()) {
+			continue
+		}
+		trusted = append(trusted, signerID)
+	}
+	return trusted, nil
+}
+
 // GetArtifactAttachmentListByLabel returns the attachment list of an artifact and the most recent uid by a provided label and signerID
 // When there are multiple attachments with same file name it adds an enumerator postfix.
 func (u *LcUser) GetArtifactAttachmentListByLabel(hash string, signerID, label string) ([]Attachment, string, error) {
@@ -413,7 +471,7 @@ func (u *LcUser) fetchAttachmentMapByLabel(hash, signerID string, attach string)
 		return nil, err
 	}
 	if len(res.Entries) < 1 {
-		return nil, errors.New("provided label does not contains entries")
+		return nil, ErrAttachmentLabelNotFound
 	}
 
 	attachMap := make(map[string][]*Attachment)
@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/codenotary/immudb/embedded/store"
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	"github.com/vchain-us/ledger-compliance-go/schema"
+	"github.com/vchain-us/vcn/pkg/meta"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// LcProofBundleVersion is the version of the proof bundle format
+const LcProofBundleVersion = 1
+
+// LcProofBundle is a self-contained proof that an artifact has been notarized on the ledger.
+// It holds the notarization entry along with its inclusion and dual proofs, and the ledger state
+// the proofs were verified against when exported, so the notarization can be verified offline.
+//
+// The proofs alone only show that the bundle is self-consistent: the ledger state is authenticated
+// by verifying its signature against the public signing key of the ledger server (see Verify).
+//
+// Only the notarization entry is covered by the proofs, while its timestamp and the API key's
+// revocation time are the ones reported by the ledger when the bundle was exported.
+type LcProofBundle struct {
+	Version int             `json:"version"`
+	Key     string          `json:"key"`
+	Item    json.RawMessage `json:"item"`
+	State   json.RawMessage `json:"state"`
+}
+
+// ExportProof fetches the notarization of hash by signerID (or by the current user, if empty),
+// verifies it against the locally trusted ledger state, and returns it as a proof bundle.
+func (u *LcUser) ExportProof(hash, signerID string) (*LcProofBundle, error) {
+	md := metadata.Pairs(
+		meta.VcnLCPluginTypeHeaderName, meta.VcnLCPluginTypeHeaderValue,
+		meta.VcnLCCmdHeaderName, meta.VcnLCVerifyCmdHeaderValue,
+	)
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	if signerID == "" {
+		signerID = GetSignerIDByApiKey(u.Client.ApiKey)
+	}
+	key := AppendSignerId(hash, AppendPrefix(meta.VcnPrefix, []byte(signerID)))
+
+	if err := u.Client.StateService.CacheLock(); err != nil {
+		return nil, err
+	}
+	defer u.Client.StateService.CacheUnlock()
+
+	state, err := u.Client.StateService.GetState(ctx, u.Client.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := u.Client.ServiceClient.VerifiableGetExt(ctx, &immuschema.VerifiableGetRequest{
+		KeyRequest:   &immuschema.KeyRequest{Key: key},
+		ProveSinceTx: state.TxId,
+	})
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Message() == "key not found" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	newState, err := verifyLcEntry(state, item.Item, key)
+	if err != nil {
+		return nil, ErrNotVerified
+	}
+	// the bundle could not be authenticated offline without the server's signature
+	if newState.Signature == nil {
+		return nil, fmt.Errorf("the ledger server does not sign its state, so no proof bundle can be exported")
+	}
+	if err := u.Client.StateService.SetState(u.Client.ApiKey, newState); err != nil {
+		return nil, err
+	}
+
+	itemJSON, err := protojson.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	stateJSON, err := protojson.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LcProofBundle{
+		Version: LcProofBundleVersion,
+		Key:     string(key),
+		Item:    itemJSON,
+		State:   stateJSON,
+	}, nil
+}
+
+// LoadProofBundle reads the proof bundle at path.
+func LoadProofBundle(path string) (*LcProofBundle, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle LcProofBundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid proof bundle %s: %s", path, err)
+	}
+	return &bundle, nil
+}
+
+// ledgerState returns the ledger state the proofs of b were verified against when exported.
+func (b *LcProofBundle) ledgerState() (*immuschema.ImmutableState, error) {
+	state := &immuschema.ImmutableState{}
+	if err := protojson.Unmarshal(b.State, state); err != nil {
+		return nil, fmt.Errorf("invalid ledger state: %s", err)
+	}
+	return state, nil
+}
+
+// Verify verifies the inclusion and dual proofs of b, without any network access,
+// and returns the notarized artifact if b proves the notarization of hash, along with the ledger state
+// the proofs lead to (i.e. the one whose signature is checked). ErrNotVerified is returned if any proof does not verify.
+//
+// If pubKey is not nil, the returned state must be signed by it, otherwise ErrNotVerified is returned.
+// If pubKey is nil, the signature is not checked: the proofs are consistent, but the ledger state
+// is not authenticated, since anyone could build a consistent bundle.
+func (b *LcProofBundle) Verify(hash string, pubKey *ecdsa.PublicKey) (lca *LcArtifact, state *immuschema.ImmutableState, err error) {
+	if b.Version != LcProofBundleVersion {
+		return nil, nil, fmt.Errorf("unsupported proof bundle version: %d", b.Version)
+	}
+	if !strings.HasPrefix(b.Key, meta.VcnPrefix+".") || !strings.HasSuffix(b.Key, "."+hash) {
+		return nil, nil, fmt.Errorf("the proof bundle does not refer to %s", hash)
+	}
+
+	item := &schema.VerifiableItemExt{}
+	if err := protojson.Unmarshal(b.Item, item); err != nil {
+		return nil, nil, fmt.Errorf("invalid proof bundle item: %s", err)
+	}
+	bundleState, err := b.ledgerState()
+	if err != nil {
+		return nil, nil, err
+	}
+	// without a trusted state, the entry's transaction could not be bound to the ledger
+	if bundleState.TxId == 0 {
+		return nil, nil, fmt.Errorf("invalid ledger state: no transaction")
+	}
+
+	newState, err := verifyLcEntry(bundleState, item.Item, []byte(b.Key))
+	if err != nil {
+		return nil, nil, ErrNotVerified
+	}
+	if pubKey != nil {
+		if ok, err := newState.CheckSignature(pubKey); err != nil || !ok {
+			return nil, nil, ErrNotVerified
+		}
+	}
+
+	lca, err = VerifiableItemExtToLcArtifact(item)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lca.Hash != hash {
+		return nil, nil, ErrNotVerified
+	}
+	return lca, newState, nil
+}
+
+// SignerID returns the SignerID of the notarization within b.
+func (b *LcProofBundle) SignerID() string {
+	id := strings.TrimPrefix(b.Key, meta.VcnPrefix+".")
+	if i := strings.LastIndex(id, "."); i >= 0 {
+		return id[:i]
+	}
+	return ""
+}
+
+// LoadSigningPubKey reads the PEM encoded public signing key of the ledger server at path.
+func LoadSigningPubKey(path string) (*ecdsa.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("invalid public signing key %s: no PEM data found", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public signing key %s: %s", path, err)
+	}
+	pubKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid public signing key %s: not an ECDSA key", path)
+	}
+	return pubKey, nil
+}
+
+// verifyLcEntry verifies that the entry is included within its transaction and that such transaction
+// is consistent with the trusted state, then returns the newly trusted state.
+// It mirrors the verification done by the ledger client when getting verified entries,
+// except for references that are not accepted.
+func verifyLcEntry(state *immuschema.ImmutableState, vEntry *immuschema.VerifiableEntry, key []byte) (*immuschema.ImmutableState, error) {
+	if vEntry == nil || vEntry.Entry == nil || vEntry.InclusionProof == nil || vEntry.VerifiableTx == nil {
+		return nil, store.ErrCorruptedData
+	}
+	if dp := vEntry.VerifiableTx.DualProof; dp == nil || dp.SourceTxMetadata == nil || dp.TargetTxMetadata == nil {
+		return nil, store.ErrCorruptedData
+	}
+	// notarizations are never stored as references, and the value of a referenced entry would not be proven
+	if vEntry.Entry.ReferencedBy != nil {
+		return nil, store.ErrCorruptedData
+	}
+
+	inclusionProof := immuschema.InclusionProofFrom(vEntry.InclusionProof)
+	dualProof := immuschema.DualProofFrom(vEntry.VerifiableTx.DualProof)
+
+	var eh [sha256.Size]byte
+	var sourceID, targetID uint64
+	var sourceAlh, targetAlh [sha256.Size]byte
+
+	vTx := vEntry.Entry.Tx
+	kv := database.EncodeKV(key, vEntry.Entry.Value)
+
+	if state.TxId <= vTx {
+		eh = immuschema.DigestFrom(vEntry.VerifiableTx.DualProof.TargetTxMetadata.EH)
+		sourceID = state.TxId
+		sourceAlh = immuschema.DigestFrom(state.TxHash)
+		targetID = vTx
+		targetAlh = dualProof.TargetTxMetadata.Alh()
+	} else {
+		eh = immuschema.DigestFrom(vEntry.VerifiableTx.DualProof.SourceTxMetadata.EH)
+		sourceID = vTx
+		sourceAlh = dualProof.SourceTxMetadata.Alh()
+		targetID = state.TxId
+		targetAlh = immuschema.DigestFrom(state.TxHash)
+	}
+
+	if !store.VerifyInclusion(inclusionProof, kv, eh) {
+		return nil, store.ErrCorruptedData
+	}
+
+	if state.TxId > 0 {
+		if !store.VerifyDualProof(dualProof, sourceID, targetID, sourceAlh, targetAlh) {
+			return nil, store.ErrCorruptedData
+		}
+	}
+
+	return &immuschema.ImmutableState{
+		TxId:      targetID,
+		TxHash:    targetAlh[:],
+		Signature: vEntry.VerifiableTx.Signature,
+	}, nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/database"
+	immulogger "github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/ledger-compliance-go/schema"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const testProofHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// testProofBundle notarizes an artifact within a local immudb store (followed by another transaction)
+// and returns the proof bundle of its entry, against the latest state of the store signed by key.
+func testProofBundle(t *testing.T, value []byte, key *ecdsa.PrivateKey) *LcProofBundle {
+	dir, err := ioutil.TempDir("", "vcn-test-proof")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	st, err := store.Open(dir, store.DefaultOptions().WithLog(immulogger.NewSimpleLogger("", ioutil.Discard)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	k := []byte("vcn.signer." + testProofHash)
+	for _, kv := range []*store.KV{
+		database.EncodeKV([]byte("before"), []byte("value")),
+		database.EncodeKV(k, value),
+		database.EncodeKV([]byte("after"), []byte("value")),
+	} {
+		if _, err := st.Commit([]*store.KV{kv}, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx := st.NewTx()
+	assert.NoError(t, st.ReadTx(2, tx))
+	stateTx := st.NewTx()
+	assert.NoError(t, st.ReadTx(3, stateTx))
+
+	inclusionProof, err := tx.Proof(database.EncodeKey(k))
+	assert.NoError(t, err)
+	dualProof, err := st.DualProof(tx, stateTx)
+	assert.NoError(t, err)
+
+	// the server signs the state the proofs lead to
+	state := &immuschema.ImmutableState{TxId: 3, TxHash: stateTx.Alh[:]}
+	sig, pub, err := signer.NewSignerFromPKey(rand.Reader, key).Sign(state.ToBytes())
+	assert.NoError(t, err)
+
+	item, err := protojson.Marshal(&schema.VerifiableItemExt{
+		Item: &immuschema.VerifiableEntry{
+			Entry: &immuschema.Entry{Tx: 2, Key: k, Value: value},
+			VerifiableTx: &immuschema.VerifiableTx{
+				Tx:        immuschema.TxTo(tx),
+				DualProof: immuschema.DualProofTo(dualProof),
+				Signature: &immuschema.Signature{Signature: sig, PublicKey: pub},
+			},
+			InclusionProof: immuschema.InclusionProofTo(inclusionProof),
+		},
+		Timestamp: &timestamp.Timestamp{Seconds: 1600000000},
+	})
+	assert.NoError(t, err)
+	stateJSON, err := protojson.Marshal(state)
+	assert.NoError(t, err)
+
+	return &LcProofBundle{
+		Version: LcProofBundleVersion,
+		Key:     string(k),
+		Item:    item,
+		State:   stateJSON,
+	}
+}
+
+func testSigningKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestProofBundleVerify(t *testing.T) {
+	key := testSigningKey(t)
+	value, _ := json.Marshal(LcArtifact{Name: "empty", Hash: testProofHash, Status: 0})
+	b := testProofBundle(t, value, key)
+
+	lca, state, err := b.Verify(testProofHash, &key.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", lca.Name)
+	assert.Equal(t, int64(1600000000), lca.Timestamp.Unix())
+	assert.Equal(t, "signer", b.SignerID())
+	// the signed state
+	assert.Equal(t, uint64(3), state.TxId)
+	ok, err := state.CheckSignature(&key.PublicKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// consistent, but not authenticated without the server's key
+	_, state, err = b.Verify(testProofHash, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), state.TxId)
+
+	// signed by another key, e.g. a forged ledger
+	_, _, err = b.Verify(testProofHash, &testSigningKey(t).PublicKey)
+	assert.Equal(t, ErrNotVerified, err)
+
+	// another hash
+	_, _, err = b.Verify("0000000000000000000000000000000000000000000000000000000000000000", &key.PublicKey)
+	assert.Error(t, err)
+
+	// tampered value
+	tampered := *b
+	item := &schema.VerifiableItemExt{}
+	assert.NoError(t, protojson.Unmarshal(b.Item, item))
+	item.Item.Entry.Value, _ = json.Marshal(LcArtifact{Name: "tampered", Hash: testProofHash})
+	tampered.Item, _ = protojson.Marshal(item)
+	_, _, err = tampered.Verify(testProofHash, nil)
+	assert.Equal(t, ErrNotVerified, err)
+
+	// no signature
+	tampered = *b
+	item = &schema.VerifiableItemExt{}
+	assert.NoError(t, protojson.Unmarshal(b.Item, item))
+	item.Item.VerifiableTx.Signature = nil
+	tampered.Item, _ = protojson.Marshal(item)
+	_, _, err = tampered.Verify(testProofHash, &key.PublicKey)
+	assert.Equal(t, ErrNotVerified, err)
+
+	// another ledger state
+	tampered = *b
+	tampered.State, _ = protojson.Marshal(&immuschema.ImmutableState{TxId: 3, TxHash: make([]byte, 32)})
+	_, _, err = tampered.Verify(testProofHash, nil)
+	assert.Equal(t, ErrNotVerified, err)
+
+	// no ledger state
+	tampered = *b
+	tampered.State = []byte("{}")
+	_, _, err = tampered.Verify(testProofHash, nil)
+	assert.Error(t, err)
+
+	// unsupported version
+	tampered = *b
+	tampered.Version = 0
+	_, _, err = tampered.Verify(testProofHash, nil)
+	assert.Error(t, err)
+}
+
+func TestLoadSigningPubKey(t *testing.T) {
+	key := testSigningKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	f, err := ioutil.TempFile("", "vcn-test-pubkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	assert.NoError(t, pem.Encode(f, &pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	f.Close()
+
+	pubKey, err := LoadSigningPubKey(f.Name())
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(pubKey))
+
+	_, err = LoadSigningPubKey(os.DevNull)
+	assert.Error(t, err)
+}
@@ -1,5 +1,11 @@
 package api
 
+import (
+	"fmt"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
 // Sign is invoked by the User to notarize an artifact using the given functional options,
 // By default, the artifact is notarized using status = meta.StatusTrusted, visibility meta.VisibilityPrivate.
 func (u LcUser) Sign(artifact Artifact, options ...LcSignOption) (bool, uint64, error) {
@@ -15,5 +21,79 @@ func (u LcUser) Sign(artifact Artifact, options ...LcSignOption) (bool, uint64,
 		return false, 0, err
 	}
 
-	return u.createArtifact(artifact, o.status, o.attach)
+	return u.createArtifact(artifact, o)
+}
+
+// Limits of a single ledger transaction, as per immudb's defaults (max entries per transaction and
+// max gRPC message size). The size limit leaves room for the request's envelope.
+const (
+	LcMaxTxEntries = 1024
+	LcMaxTxSize    = 4<<20 - 64<<10
+)
+
+// SignBatch is invoked by the User to notarize the artifacts within a single ledger transaction,
+// using the given functional options (see Sign), so that either all or none of them are notarized.
+// An error is returned, and nothing is notarized, if the entries of all artifacts (including attachments
+// and labels) exceed LcMaxTxEntries or LcMaxTxSize.
+//
+// The returned transaction ID is the same for all artifacts. If an artifact is listed more than once,
+// the last occurrence wins, as it would happen by signing the artifacts one by one.
+func (u LcUser) SignBatch(artifacts []Artifact, options ...LcSignOption) (uint64, error) {
+	o, err := makeLcSignOpts(options...)
+	if err != nil {
+		return 0, err
+	}
+
+	groups := make([][]*immuschema.KeyValue, len(artifacts))
+	for i, artifact := range artifacts {
+		if artifact.Hash == "" {
+			return 0, makeError("hash is missing", nil)
+		}
+		if groups[i], err = u.artifactKVs(artifact, o); err != nil {
+			return 0, err
+		}
+	}
+
+	// duplicated keys are not allowed within a transaction
+	kvs := dedupKVs(groups)
+
+	size := 0
+	for _, kv := range kvs {
+		size += len(kv.Key) + len(kv.Value)
+	}
+	if len(kvs) > LcMaxTxEntries || size > LcMaxTxSize {
+		return 0, makeError(fmt.Sprintf(
+			"the assets exceed the limits of a single ledger transaction (%d entries or %d bytes), "+
+				"so they cannot be notarized atomically", LcMaxTxEntries, LcMaxTxSize), nil)
+	}
+
+	req := &immuschema.SetRequest{KVs: kvs}
+	txMeta, err := u.Client.SetAll(lcNotarizeContext(), req)
+	if err != nil {
+		return 0, err
+	}
+	return txMeta.Id, nil
+}
+
+// dedupKVs returns the entries of all groups, in order, keeping only the last entry set for each key.
+func dedupKVs(groups [][]*immuschema.KeyValue) []*immuschema.KeyValue {
+	last := map[string]int{}
+	n := 0
+	for _, kvs := range groups {
+		for _, kv := range kvs {
+			last[string(kv.Key)] = n
+			n++
+		}
+	}
+	deduped := make([]*immuschema.KeyValue, 0, len(last))
+	n = 0
+	for _, kvs := range groups {
+		for _, kv := range kvs {
+			if last[string(kv.Key)] == n {
+				deduped = append(deduped, kv)
+			}
+			n++
+		}
+	}
+	return deduped
 }
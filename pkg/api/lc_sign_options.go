@@ -9,6 +9,9 @@
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/vchain-us/vcn/pkg/meta"
 )
 
@@ -19,6 +22,8 @@ type lcSignOpts struct {
 	status     meta.Status
 	visibility meta.Visibility
 	attach     []string
+	validFrom  *time.Time
+	validUntil *time.Time
 }
 
 func makeLcSignOpts(opts ...LcSignOption) (o *lcSignOpts, err error) {
@@ -63,3 +68,22 @@ func LcSignWithAttachments(attach []string) LcSignOption {
 		return nil
 	}
 }
+
+// LcSignWithValidity returns the functional option for the given validity window.
+// Either bound can be nil, meaning the window is open on that side.
+func LcSignWithValidity(from, until *time.Time) LcSignOption {
+	return func(o *lcSignOpts) error {
+		if from != nil && until != nil && !from.Before(*until) {
+			return fmt.Errorf("the validity window is empty, %s is not before %s", from.Format(time.RFC3339), until.Format(time.RFC3339))
+		}
+		if from != nil {
+			t := from.UTC()
+			o.validFrom = &t
+		}
+		if until != nil {
+			t := until.UTC()
+			o.validUntil = &t
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLcSignWithValidity(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	until := from.Add(24 * time.Hour)
+
+	o := &lcSignOpts{}
+	assert.NoError(t, LcSignWithValidity(&from, &until)(o))
+	assert.True(t, from.Equal(*o.validFrom))
+	assert.Equal(t, time.UTC, o.validFrom.Location())
+	assert.True(t, until.Equal(*o.validUntil))
+
+	// open windows
+	o = &lcSignOpts{}
+	assert.NoError(t, LcSignWithValidity(nil, &until)(o))
+	assert.Nil(t, o.validFrom)
+	assert.NotNil(t, o.validUntil)
+
+	// empty window
+	assert.Error(t, LcSignWithValidity(&until, &from)(&lcSignOpts{}))
+	assert.Error(t, LcSignWithValidity(&from, &from)(&lcSignOpts{}))
+}
+
+func TestLcArtifactValid(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(24 * time.Hour)
+
+	lca := &LcArtifact{}
+	assert.True(t, lca.Valid(from))
+
+	lca = &LcArtifact{ValidFrom: &from, ValidUntil: &until}
+	assert.False(t, lca.Valid(from.Add(-time.Second)))
+	assert.True(t, lca.Valid(from))
+	assert.True(t, lca.Valid(until.Add(-time.Second)))
+	assert.False(t, lca.Valid(until))
+
+	lca = &LcArtifact{ValidUntil: &until}
+	assert.True(t, lca.Valid(time.Time{}))
+	assert.False(t, lca.Valid(until.Add(time.Hour)))
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+	sdk "github.com/vchain-us/ledger-compliance-go/grpcclient"
+)
+
+func testKVs(keys ...string) []*immuschema.KeyValue {
+	kvs := make([]*immuschema.KeyValue, len(keys))
+	for i, k := range keys {
+		kvs[i] = &immuschema.KeyValue{Key: []byte(k), Value: []byte("value")}
+	}
+	return kvs
+}
+
+func TestDedupKVs(t *testing.T) {
+	groups := [][]*immuschema.KeyValue{
+		testKVs("a", "a.attach"),
+		testKVs("b", "b.attach", "b.attach"),
+		nil,
+		testKVs("a", "a.label"),
+	}
+
+	assert.Equal(t, testKVs("a.attach", "b", "b.attach", "a", "a.label"), dedupKVs(groups))
+	assert.Empty(t, dedupKVs(nil))
+}
+
+func TestSignBatchLimits(t *testing.T) {
+	u := LcUser{Client: &sdk.LcClient{ApiKey: "signer.apikey"}}
+
+	// a single artifact exceeding the size limit is refused before reaching the ledger
+	big := Artifact{Hash: "hash", Metadata: Metadata{"data": strings.Repeat("x", LcMaxTxSize)}}
+	_, err := u.SignBatch([]Artifact{big})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "single ledger transaction")
+	}
+
+	// as well as too many entries
+	artifacts := make([]Artifact, LcMaxTxEntries+1)
+	for i := range artifacts {
+		artifacts[i] = Artifact{Hash: fmt.Sprintf("hash%d", i)}
+	}
+	_, err = u.SignBatch(artifacts)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "single ledger transaction")
+	}
+}
@@ -10,6 +10,7 @@ package bundle
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dustin/go-humanize"
@@ -60,103 +61,206 @@ func (r *diffReporter) String() string {
 	return strings.Join(r.lines, "\n")
 }
 
-// DiffByPath returns a human-readable report as string containing
-// additions, modifications, renamings, deletions of x.Items relative to m.Items
-// listed by path.
-//
-// Do not depend on this output being stable.
-func (m Manifest) DiffByPath(x Manifest) (report string, equal bool, err error) {
-	type modDiff struct {
-		path string
-		from Descriptor
-		to   Descriptor
-	}
+// DiffItem is an item of a ManifestDiff, by path.
+type DiffItem struct {
+	Path   string        `json:"path" yaml:"path"`
+	Digest digest.Digest `json:"digest" yaml:"digest"`
+	Size   uint64        `json:"size" yaml:"size"`
+}
 
-	type pathDiff struct {
-		desc Descriptor
-		from string
-		to   string
-	}
+// DiffContent is the content of a modified path.
+type DiffContent struct {
+	Digest digest.Digest `json:"digest" yaml:"digest"`
+	Size   uint64        `json:"size" yaml:"size"`
+}
 
-	type itemDiff struct {
-		path string
-		desc Descriptor
-	}
+// DiffModified is a path whose content has been modified.
+type DiffModified struct {
+	Path string      `json:"path" yaml:"path"`
+	From DiffContent `json:"from" yaml:"from"`
+	To   DiffContent `json:"to" yaml:"to"`
+}
 
-	adds := make([]itemDiff, 0)
-	mods := make([]modDiff, 0)
-	rens := make([]pathDiff, 0)
-	dels := make([]itemDiff, 0)
+// DiffRenamed is a content whose path has been changed.
+type DiffRenamed struct {
+	From   string        `json:"from" yaml:"from"`
+	To     string        `json:"to" yaml:"to"`
+	Digest digest.Digest `json:"digest" yaml:"digest"`
+	Size   uint64        `json:"size" yaml:"size"`
+}
 
-	mByPath := make(map[string]Descriptor)
-	xByPath := make(map[string]Descriptor)
-	newPaths := make(map[digest.Digest][]string)
+// DiffEntry is a changed entry (schema version 2 only): From is nil for new entries,
+// To is nil for deleted ones. Additions and deletions of file entries are not included,
+// since they are already reported by items.
+type DiffEntry struct {
+	Path string `json:"path" yaml:"path"`
+	From *Entry `json:"from,omitempty" yaml:"from,omitempty"`
+	To   *Entry `json:"to,omitempty" yaml:"to,omitempty"`
+}
 
-	for _, d := range x.Items {
-		for _, path := range d.Paths {
-			xByPath[path] = d
-		}
+// ManifestDiff holds the changes of a manifest relative to a previous one, listed by path.
+// Each list is sorted by path.
+type ManifestDiff struct {
+	FromSchemaVersion uint           `json:"fromSchemaVersion" yaml:"fromSchemaVersion"`
+	ToSchemaVersion   uint           `json:"toSchemaVersion" yaml:"toSchemaVersion"`
+	Added             []DiffItem     `json:"added" yaml:"added"`
+	Modified          []DiffModified `json:"modified" yaml:"modified"`
+	Renamed           []DiffRenamed  `json:"renamed" yaml:"renamed"`
+	Deleted           []DiffItem     `json:"deleted" yaml:"deleted"`
+	Entries           []DiffEntry    `json:"entries" yaml:"entries"`
+}
+
+// Equal returns true if there are no changes.
+func (d ManifestDiff) Equal() bool {
+	return d.FromSchemaVersion == d.ToSchemaVersion &&
+		len(d.Added) == 0 &&
+		len(d.Modified) == 0 &&
+		len(d.Renamed) == 0 &&
+		len(d.Deleted) == 0 &&
+		len(d.Entries) == 0
+}
+
+// PathDiff returns the additions, modifications, renamings, deletions of m.Items relative to x.Items
+// listed by path. For schema version 2 manifests, changes of m.Entries relative to x.Entries
+// (i.e. modes, symlinks and empty directories) are listed too.
+//
+// A path missing in m is reported as renamed when a new path in m has the same content,
+// otherwise as deleted.
+func (m Manifest) PathDiff(x Manifest) *ManifestDiff {
+	d := &ManifestDiff{
+		FromSchemaVersion: x.SchemaVersion,
+		ToSchemaVersion:   m.SchemaVersion,
+		Added:             make([]DiffItem, 0),
+		Modified:          make([]DiffModified, 0),
+		Renamed:           make([]DiffRenamed, 0),
+		Deleted:           make([]DiffItem, 0),
+		Entries:           make([]DiffEntry, 0),
 	}
-	for _, d := range m.Items {
-		for _, path := range d.Paths {
-			mByPath[path] = d
-			if _, ok := xByPath[path]; !ok {
-				newPaths[d.Digest] = append(newPaths[d.Digest], path)
-			}
+
+	mByPath := itemsByPath(m)
+	xByPath := itemsByPath(x)
+
+	// new paths by digest, sorted so that renamings are stable
+	newPaths := make(map[digest.Digest][]string)
+	for _, path := range sortedPaths(mByPath) {
+		if _, ok := xByPath[path]; !ok {
+			dd := mByPath[path].Digest
+			newPaths[dd] = append(newPaths[dd], path)
 		}
 	}
 
-	for path, xd := range xByPath {
+	for _, path := range sortedPaths(xByPath) {
+		xd := xByPath[path]
 
 		// try by path
 		if md, ok := mByPath[path]; ok {
 			if md.Digest != xd.Digest {
-				// modified
-				mods = append(mods, modDiff{
-					path: path,
-					from: xd,
-					to:   md,
+				d.Modified = append(d.Modified, DiffModified{
+					Path: path,
+					From: DiffContent{Digest: xd.Digest, Size: xd.Size},
+					To:   DiffContent{Digest: md.Digest, Size: md.Size},
 				})
 			}
 			// else:
 			// same content, so no diff
-
-		} else { // try by digest
-			byDig := xd.Digest
-			if mPaths, ok := newPaths[byDig]; ok && len(mPaths) > 0 {
-				// renamed
-				newPath := mPaths[0]
-				newPaths[byDig] = mPaths[1:]
-				rens = append(rens, pathDiff{
-					desc: xd,
-					from: path,
-					to:   newPath,
-				})
-				delete(mByPath, newPath)
-			} else {
-				// deleted
-				dels = append(dels, itemDiff{
-					path: path,
-					desc: xd,
-				})
-			}
-
+		} else if mPaths := newPaths[xd.Digest]; len(mPaths) > 0 { // try by digest
+			newPath := mPaths[0]
+			newPaths[xd.Digest] = mPaths[1:]
+			d.Renamed = append(d.Renamed, DiffRenamed{
+				From:   path,
+				To:     newPath,
+				Digest: xd.Digest,
+				Size:   xd.Size,
+			})
+			delete(mByPath, newPath)
+		} else {
+			d.Deleted = append(d.Deleted, newDiffItem(path, xd))
 		}
 
 		delete(mByPath, path)
 	}
 
 	// finally, arrange new items
-	for path, d := range mByPath {
-		adds = append(adds, itemDiff{
-			path: path,
-			desc: d,
-		})
+	for _, path := range sortedPaths(mByPath) {
+		d.Added = append(d.Added, newDiffItem(path, mByPath[path]))
+	}
+
+	if m.SchemaVersion == x.SchemaVersion {
+		d.Entries = diffEntries(m, x)
+	}
+	return d
+}
+
+func newDiffItem(path string, d Descriptor) DiffItem {
+	return DiffItem{
+		Path:   path,
+		Digest: d.Digest,
+		Size:   d.Size,
+	}
+}
+
+func itemsByPath(m Manifest) map[string]Descriptor {
+	byPath := make(map[string]Descriptor)
+	for _, d := range m.Items {
+		for _, path := range d.Paths {
+			byPath[path] = d
+		}
+	}
+	return byPath
+}
+
+func sortedPaths(byPath map[string]Descriptor) []string {
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
+	return paths
+}
 
-	equal = len(adds) == 0 && len(mods) == 0 && len(rens) == 0 && len(dels) == 0
-	if equal {
-		return // empty diff, no need to format lines
+// diffEntries returns the changes of m.Entries relative to x.Entries, sorted by path.
+func diffEntries(m Manifest, x Manifest) []DiffEntry {
+	mByPath := make(map[string]Entry, len(m.Entries))
+	for _, e := range m.Entries {
+		mByPath[e.Path] = e
+	}
+	xByPath := make(map[string]Entry, len(x.Entries))
+	paths := make([]string, 0, len(x.Entries))
+	for _, e := range x.Entries {
+		xByPath[e.Path] = e
+		paths = append(paths, e.Path)
+	}
+	for _, e := range m.Entries {
+		if _, ok := xByPath[e.Path]; !ok {
+			paths = append(paths, e.Path)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := make([]DiffEntry, 0)
+	for _, path := range paths {
+		me, inM := mByPath[path]
+		xe, inX := xByPath[path]
+		switch {
+		case inM && inX:
+			if me != xe {
+				diffs = append(diffs, DiffEntry{Path: path, From: &xe, To: &me})
+			}
+		case inM && me.Type != EntryTypeFile:
+			diffs = append(diffs, DiffEntry{Path: path, To: &me})
+		case inX && xe.Type != EntryTypeFile:
+			diffs = append(diffs, DiffEntry{Path: path, From: &xe})
+		}
+	}
+	return diffs
+}
+
+// String returns a human-readable report of d.
+//
+// Do not depend on this output being stable.
+func (d ManifestDiff) String() string {
+	if d.Equal() {
+		return ""
 	}
 
 	lines := []string{}
@@ -165,42 +269,65 @@ func (m Manifest) DiffByPath(x Manifest) (report string, equal bool, err error)
 		lines = append(lines, fmt.Sprintf(format, a...))
 	}
 
-	for _, d := range adds {
+	for _, i := range d.Added {
 		sprintf(
 			"\tnew item:   %s (%s)\n\t            + %s",
-			d.path,
-			humanize.Bytes(d.desc.Size),
-			d.desc.Digest.String(),
+			i.Path,
+			humanize.Bytes(i.Size),
+			i.Digest.String(),
 		)
 	}
-	for _, d := range mods {
+	for _, i := range d.Modified {
 		sprintf(
 			"\tmodified:   %s (%s -> %s)\n\t            - %s\n\t            + %s",
-			d.path,
-			humanize.Bytes(d.from.Size),
-			humanize.Bytes(d.to.Size),
-			d.from.Digest.String(),
-			d.to.Digest.String(),
+			i.Path,
+			humanize.Bytes(i.From.Size),
+			humanize.Bytes(i.To.Size),
+			i.From.Digest.String(),
+			i.To.Digest.String(),
 		)
 	}
-	for _, d := range rens {
+	for _, i := range d.Renamed {
 		sprintf(
 			"\trenamed:    %s -> %s (%s)\n\t            = %s",
-			d.from,
-			d.to,
-			humanize.Bytes(d.desc.Size),
-			d.desc.Digest.String(),
+			i.From,
+			i.To,
+			humanize.Bytes(i.Size),
+			i.Digest.String(),
 		)
 	}
-	for _, d := range dels {
+	for _, i := range d.Deleted {
 		sprintf(
 			"\tdeleted:    %s (%s)\n\t            - %s",
-			d.path,
-			humanize.Bytes(d.desc.Size),
-			d.desc.Digest.String(),
+			i.Path,
+			humanize.Bytes(i.Size),
+			i.Digest.String(),
 		)
 	}
+	if d.FromSchemaVersion != d.ToSchemaVersion {
+		sprintf("\tschema:     version %d -> %d", d.FromSchemaVersion, d.ToSchemaVersion)
+	}
+	for _, e := range d.Entries {
+		switch {
+		case e.From != nil && e.To != nil:
+			sprintf("\tchanged:    %s (%s -> %s)", e.Path, *e.From, *e.To)
+		case e.To != nil:
+			sprintf("\tnew entry:  %s (%s)", e.Path, *e.To)
+		default:
+			sprintf("\tdeleted:    %s (%s)", e.Path, *e.From)
+		}
+	}
 
-	report = strings.Join(lines, "\n\n") + "\n"
-	return
+	return strings.Join(lines, "\n\n") + "\n"
+}
+
+// DiffByPath returns a human-readable report as string containing
+// additions, modifications, renamings, deletions of x.Items relative to m.Items
+// listed by path. For schema version 2 manifests, changes of x.Entries relative to m.Entries
+// (i.e. modes, symlinks and empty directories) are listed too.
+//
+// See PathDiff for the structured diff. Do not depend on this output being stable.
+func (m Manifest) DiffByPath(x Manifest) (report string, equal bool, err error) {
+	d := m.PathDiff(x)
+	return d.String(), d.Equal(), nil
 }
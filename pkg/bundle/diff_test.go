@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package bundle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManifest(t *testing.T, files map[string]string) *Manifest {
+	items := make([]Descriptor, 0)
+	for path, content := range files {
+		d, err := NewDescriptor(path, strings.NewReader(content))
+		assert.NoError(t, err)
+		items = append(items, *d)
+	}
+	m := NewManifest(items...)
+	assert.NoError(t, m.Normalize())
+	return m
+}
+
+func TestPathDiff(t *testing.T) {
+	x := newTestManifest(t, map[string]string{
+		"same":     "same",
+		"modified": "old",
+		"renamed":  "renamed",
+		"deleted":  "deleted",
+	})
+	m := newTestManifest(t, map[string]string{
+		"same":       "same",
+		"modified":   "new",
+		"renamed-to": "renamed",
+		"added":      "added",
+	})
+
+	d := m.PathDiff(*x)
+	assert.False(t, d.Equal())
+	assert.Len(t, d.Added, 1)
+	assert.Equal(t, "added", d.Added[0].Path)
+	assert.Equal(t, uint64(5), d.Added[0].Size)
+	assert.Len(t, d.Modified, 1)
+	assert.Equal(t, "modified", d.Modified[0].Path)
+	assert.NotEqual(t, d.Modified[0].From.Digest, d.Modified[0].To.Digest)
+	assert.Len(t, d.Renamed, 1)
+	assert.Equal(t, "renamed", d.Renamed[0].From)
+	assert.Equal(t, "renamed-to", d.Renamed[0].To)
+	assert.Len(t, d.Deleted, 1)
+	assert.Equal(t, "deleted", d.Deleted[0].Path)
+	assert.Empty(t, d.Entries)
+
+	// the report is the same of DiffByPath
+	report, equal, err := m.DiffByPath(*x)
+	assert.NoError(t, err)
+	assert.False(t, equal)
+	assert.Equal(t, d.String(), report)
+	assert.Contains(t, report, "renamed:    renamed -> renamed-to")
+
+	// no changes
+	d = x.PathDiff(*x)
+	assert.True(t, d.Equal())
+	assert.Empty(t, d.String())
+	assert.Empty(t, d.Patch())
+}
+
+func TestPatch(t *testing.T) {
+	x := newTestManifest(t, map[string]string{
+		"modified": "old",
+		"renamed":  "renamed",
+		"deleted":  "deleted",
+	})
+	m := newTestManifest(t, map[string]string{
+		"modified":   "new",
+		"renamed-to": "renamed",
+		"added":      "added",
+	})
+	x = NewManifestV2(x.Items, []Entry{
+		{Path: "modified", Type: EntryTypeFile, Mode: 0644},
+		{Path: "renamed", Type: EntryTypeFile, Mode: 0644},
+		{Path: "deleted", Type: EntryTypeFile, Mode: 0644},
+		{Path: "link", Type: EntryTypeSymlink, Target: "modified"},
+	})
+	m = NewManifestV2(m.Items, []Entry{
+		{Path: "modified", Type: EntryTypeFile, Mode: 0755},
+		{Path: "renamed-to", Type: EntryTypeFile, Mode: 0644},
+		{Path: "added", Type: EntryTypeFile, Mode: 0644},
+		{Path: "link", Type: EntryTypeSymlink, Target: "added"},
+		{Path: "empty", Type: EntryTypeDir, Mode: 0755},
+	})
+	assert.NoError(t, x.Normalize())
+	assert.NoError(t, m.Normalize())
+
+	d := m.PathDiff(*x)
+	assert.Len(t, d.Entries, 3)
+
+	assert.Equal(t, `diff --git a/modified b/modified
+--- a/modified
++++ b/modified
+@@ -1,1 +1,1 @@
+-sha256:cba06b5736faf67e54b07b561eae94395e774c517a7d910a54369e1263ccfbd4 3
++sha256:11507a0e2f5e69d5dfa40a62a1bd7b6ee57e6bcd85c67c9b8431b36fff21c437 3
+diff --git a/added b/added
+--- /dev/null
++++ b/added
+@@ -0,0 +1,1 @@
++sha256:279b8a60f444fa8b6275687ce7e44363d97f72f88e4a3285baf0d9ed812e4061 5
+diff --git a/deleted b/deleted
+--- a/deleted
++++ /dev/null
+@@ -1,1 +0,0 @@
+-sha256:1185f37d33b0f89e331f101a51bb8e51165c7efda15950b86a3ebcbb363f898e 7
+diff --git a/renamed b/renamed-to
+similarity index 100%
+rename from renamed
+rename to renamed-to
+diff --git a/empty b/empty
+new file mode 040755
+diff --git a/link b/link
+--- a/link
++++ b/link
+@@ -1,1 +1,1 @@
+-modified
++added
+diff --git a/modified b/modified
+old mode 100644
+new mode 100755
+`, d.Patch())
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+)
+
+// EntryType is the type of file system object an Entry refers to.
+type EntryType string
+
+const (
+	// EntryTypeFile is the entry's type for regular files, whose content is described by the manifest's items.
+	EntryTypeFile EntryType = "file"
+
+	// EntryTypeSymlink is the entry's type for symbolic links, that are not followed.
+	EntryTypeSymlink EntryType = "symlink"
+
+	// EntryTypeDir is the entry's type for empty directories.
+	EntryTypeDir EntryType = "dir"
+)
+
+// Entry describes the file system attributes of a path (schema version 2 only).
+type Entry struct {
+	// Path is the relative location of the entry.
+	Path string `json:"path"`
+
+	// Type is the type of the entry.
+	Type EntryType `json:"type"`
+
+	// Mode holds the unix permission bits of the entry (not used for symlinks).
+	Mode uint32 `json:"mode,omitempty"`
+
+	// Target is the target of the symlink, as is (symlinks only).
+	Target string `json:"target,omitempty"`
+}
+
+// UnixMode returns the unix permission bits (including setuid, setgid and sticky bits) of mode.
+func UnixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		m |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 01000
+	}
+	return m
+}
+
+func (e Entry) String() string {
+	switch e.Type {
+	case EntryTypeSymlink:
+		return fmt.Sprintf("%s -> %s", e.Type, e.Target)
+	default:
+		return fmt.Sprintf("%s %04o", e.Type, e.Mode)
+	}
+}
+
+// validate checks e against the schema version 2 specs of a single entry.
+func (e Entry) validate() error {
+	if e.Path == "" {
+		return fmt.Errorf("empty entry path in manifest")
+	}
+	switch e.Type {
+	case EntryTypeFile, EntryTypeDir:
+		if e.Target != "" {
+			return fmt.Errorf("unexpected target for %s entry in manifest: %s", e.Type, e.Path)
+		}
+	case EntryTypeSymlink:
+		if e.Target == "" {
+			return fmt.Errorf("missing target for symlink entry in manifest: %s", e.Path)
+		}
+		if e.Mode != 0 {
+			return fmt.Errorf("unexpected mode for symlink entry in manifest: %s", e.Path)
+		}
+	default:
+		return fmt.Errorf("unsupported entry type in manifest: %s", e.Type)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"sort"
+	"strings"
 
 	// See https://github.com/opencontainers/go-digest#usage
 	_ "crypto/sha256"
@@ -22,8 +23,16 @@ import (
 )
 
 const (
-	// ManifestSchemaVersion is the current manifest schema version.
-	ManifestSchemaVersion = 1
+	// ManifestSchemaVersion1 is the manifest schema version recording regular files only.
+	ManifestSchemaVersion1 = 1
+
+	// ManifestSchemaVersion2 is the manifest schema version recording regular files, file modes,
+	// symlinks and empty directories.
+	ManifestSchemaVersion2 = 2
+
+	// ManifestSchemaVersion is the default manifest schema version.
+	// It's kept to version 1, since the schema version determines the manifest's digest.
+	ManifestSchemaVersion = ManifestSchemaVersion1
 
 	// ManifestFilename is the default filename for manifest when stored.
 	ManifestFilename = ".vcn.manifest.json"
@@ -44,6 +53,17 @@ const (
 //  - json representation of the manifest MUST NOT be indented
 //  - sha256 is the only digest's algorithm that MUST be used
 //
+// Specifications (version 2), in addition to version 1 ones (except for `schemaVersion` that MUST be 2):
+//  - `entries` MUST be sorted by path (lexically byte-wise)
+//  - multiple `entries` MUST NOT have the same path
+//  - `entries.type` MUST be one of `file`, `symlink` or `dir`
+//  - every `items.paths`'s element MUST have a `file` entry, and every `file` entry MUST have a matching `items.paths`'s element
+//  - `entries.mode` holds the unix permission bits (including setuid, setgid and sticky bits), and it MUST be omitted for symlinks
+//  - `entries.target` MUST be set for symlinks only, to the link's target as is
+//  - `dir` entries are for empty directories only, so no other entry's path can be within a `dir` entry's path
+//
+// For version 1, `entries` MUST be empty (thus omitted), so its json representation is not affected.
+//
 // The Normalize() method provides sorting funcionality and specification enforcement. It's implictly called
 // when the manifest is marshalled.
 type Manifest struct {
@@ -52,6 +72,9 @@ type Manifest struct {
 
 	// Items is an ordered list of items referenced by the manifest.
 	Items []Descriptor `json:"items"`
+
+	// Entries is an ordered list of paths' attributes (schema version 2 only).
+	Entries []Entry `json:"entries,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -73,7 +96,13 @@ func (m *Manifest) Normalize() error {
 		return fmt.Errorf("nil manifest")
 	}
 
-	if m.SchemaVersion != ManifestSchemaVersion {
+	switch m.SchemaVersion {
+	case ManifestSchemaVersion1:
+		if len(m.Entries) > 0 {
+			return fmt.Errorf("entries are not supported by bundle.Manifest schema version: %d", m.SchemaVersion)
+		}
+	case ManifestSchemaVersion2:
+	default:
 		return fmt.Errorf("unsupported bundle.Manifest schema version: %d", m.SchemaVersion)
 	}
 
@@ -124,6 +153,53 @@ func (m *Manifest) Normalize() error {
 	sort.SliceStable(m.Items, func(k, j int) bool {
 		return m.Items[k].Digest.String() < m.Items[j].Digest.String()
 	})
+
+	if m.SchemaVersion == ManifestSchemaVersion2 {
+		return m.normalizeEntries(paths)
+	}
+	return nil
+}
+
+// normalizeEntries sorts entries by path and enforces schema version 2 specs,
+// where itemPaths are the paths of all items.
+func (m *Manifest) normalizeEntries(itemPaths map[string]bool) error {
+	if m.Entries == nil {
+		m.Entries = make([]Entry, 0)
+	}
+	sort.SliceStable(m.Entries, func(k, j int) bool {
+		return m.Entries[k].Path < m.Entries[j].Path
+	})
+
+	files := 0
+	for i, e := range m.Entries {
+		if err := e.validate(); err != nil {
+			return err
+		}
+		if i > 0 && m.Entries[i-1].Path == e.Path {
+			return fmt.Errorf("duplicate entry path in manifest: %s", e.Path)
+		}
+		if (e.Type == EntryTypeFile) != itemPaths[e.Path] {
+			return fmt.Errorf("entry and items mismatch in manifest: %s", e.Path)
+		}
+		if e.Type == EntryTypeFile {
+			files++
+		}
+		// since entries are sorted, paths within a dir follow the dir itself
+		// (only separated by paths sorting before "/", that are prefixed by the dir's path too)
+		if e.Type == EntryTypeDir {
+			for _, ee := range m.Entries[i+1:] {
+				if !strings.HasPrefix(ee.Path, e.Path) {
+					break
+				}
+				if strings.HasPrefix(ee.Path, e.Path+"/") {
+					return fmt.Errorf("non empty dir entry in manifest: %s", e.Path)
+				}
+			}
+		}
+	}
+	if files != len(itemPaths) {
+		return fmt.Errorf("entry and items mismatch in manifest: missing file entries")
+	}
 	return nil
 }
 
@@ -148,6 +224,21 @@ func NewManifest(items ...Descriptor) *Manifest {
 	}
 }
 
+// NewManifestV2 returns a new schema version 2 Manifest with items and entries.
+func NewManifestV2(items []Descriptor, entries []Entry) *Manifest {
+	if items == nil {
+		items = make([]Descriptor, 0)
+	}
+	if entries == nil {
+		entries = make([]Entry, 0)
+	}
+	return &Manifest{
+		SchemaVersion: ManifestSchemaVersion2,
+		Items:         items,
+		Entries:       entries,
+	}
+}
+
 // WriteManifest writes manifest's data to a file named by filename.
 func WriteManifest(manifest Manifest, filename string) error {
 	data, err := json.Marshal(&manifest)
@@ -119,3 +119,89 @@ func TestManifestSameDigestMultipleSizes(t *testing.T) {
 	}
 	assert.Error(t, m.Normalize())
 }
+
+// getTestManifestV2 returns a normalized manifest, so entries are sorted by path:
+// digits.txt, dup-digits.txt, empty, letters.txt, link
+func getTestManifestV2(t *testing.T) *Manifest {
+	m := NewManifestV2(getTestManifest(t).Items, []Entry{
+		{Path: "letters.txt", Type: EntryTypeFile, Mode: 0644},
+		{Path: "digits.txt", Type: EntryTypeFile, Mode: 0755},
+		{Path: "dup-digits.txt", Type: EntryTypeFile, Mode: 0644},
+		{Path: "link", Type: EntryTypeSymlink, Target: "letters.txt"},
+		{Path: "empty", Type: EntryTypeDir, Mode: 0755},
+	})
+	assert.NoError(t, m.Normalize())
+	return m
+}
+
+func TestManifestV2(t *testing.T) {
+	m := getTestManifestV2(t)
+
+	j, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"schemaVersion":2,"items":[{"digest":"sha256:bef57ec7f53a6d40beb640a780a639c83bc29ac8a9816f1fc6c5c6dcd93c4721","size":6,"paths":["letters.txt"]},{"digest":"sha256:c775e7b757ede630cd0aa1113bd102661ab38829ca52a6422ab782862f268646","size":10,"paths":["digits.txt","dup-digits.txt"]}],"entries":[{"path":"digits.txt","type":"file","mode":493},{"path":"dup-digits.txt","type":"file","mode":420},{"path":"empty","type":"dir","mode":493},{"path":"letters.txt","type":"file","mode":420},{"path":"link","type":"symlink","target":"letters.txt"}]}`,
+		string(j),
+	)
+
+	// modes are part of the digest
+	d, err := m.Digest()
+	assert.NoError(t, err)
+	m.Entries[0].Mode = 0644
+	dd, err := m.Digest()
+	assert.NoError(t, err)
+	assert.NotEqual(t, d, dd)
+
+	// v1 does not allow entries
+	m.SchemaVersion = ManifestSchemaVersion1
+	assert.Error(t, m.Normalize())
+}
+
+func TestManifestV2Specs(t *testing.T) {
+	for name, modify := range map[string]func(m *Manifest){
+		"missing file entry":   func(m *Manifest) { m.Entries = m.Entries[1:] },
+		"file entry w/o item":  func(m *Manifest) { m.Entries = append(m.Entries, Entry{Path: "other", Type: EntryTypeFile}) },
+		"duplicate entry":      func(m *Manifest) { m.Entries = append(m.Entries, m.Entries[0]) },
+		"symlink w/o target":   func(m *Manifest) { m.Entries[4].Target = "" },
+		"symlink with mode":    func(m *Manifest) { m.Entries[4].Mode = 0777 },
+		"target for non link":  func(m *Manifest) { m.Entries[2].Target = "x" },
+		"unknown type":         func(m *Manifest) { m.Entries[2].Type = "fifo" },
+		"non empty dir":        func(m *Manifest) { m.Entries = append(m.Entries, Entry{Path: "empty/link", Type: EntryTypeSymlink, Target: "x"}) },
+		"dir entry for a file": func(m *Manifest) { m.Entries[0].Type = EntryTypeDir },
+	} {
+		m := getTestManifestV2(t)
+		assert.NoError(t, m.Normalize(), name)
+		modify(m)
+		assert.Error(t, m.Normalize(), name)
+	}
+
+	// sibling sorting between a dir and its content does not matter
+	m := getTestManifestV2(t)
+	m.Entries = append(m.Entries, Entry{Path: "empty-not", Type: EntryTypeSymlink, Target: "x"})
+	assert.NoError(t, m.Normalize())
+}
+
+func TestManifestV2DiffByPath(t *testing.T) {
+	x := getTestManifestV2(t)
+	m := getTestManifestV2(t)
+	report, equal, err := m.DiffByPath(*x)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+	assert.Empty(t, report)
+
+	m.Entries[0].Mode = 0644
+	m.Entries[4].Target = "digits.txt"
+	m.Entries = append(m.Entries[:2], m.Entries[3:]...)
+	report, equal, err = m.DiffByPath(*x)
+	assert.NoError(t, err)
+	assert.False(t, equal)
+	assert.Contains(t, report, "changed:    digits.txt (file 0755 -> file 0644)")
+	assert.Contains(t, report, "changed:    link (symlink -> letters.txt -> symlink -> digits.txt)")
+	assert.Contains(t, report, "deleted:    empty (dir 0755)")
+
+	report, equal, err = getTestManifest(t).DiffByPath(*x)
+	assert.NoError(t, err)
+	assert.False(t, equal)
+	assert.Contains(t, report, "schema:     version 2 -> 1")
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// Patch returns d as a unified diff, with git-style extended headers.
+//
+// Since manifests do not hold files' content, each file is represented by a single line
+// made of its digest and size, while symlinks are represented by their target.
+// Renamings and mode changes are reported by extended headers only, as git does.
+func (d ManifestDiff) Patch() string {
+	var b strings.Builder
+
+	header := func(from, to string) {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", from, to)
+	}
+	hunk := func(from, to string, removed, added []string) {
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(len(removed)), hunkRange(len(added)))
+		for _, l := range removed {
+			fmt.Fprintf(&b, "-%s\n", l)
+		}
+		for _, l := range added {
+			fmt.Fprintf(&b, "+%s\n", l)
+		}
+	}
+
+	for _, i := range d.Modified {
+		header(i.Path, i.Path)
+		hunk("a/"+i.Path, "b/"+i.Path, []string{contentLine(i.From.Digest, i.From.Size)}, []string{contentLine(i.To.Digest, i.To.Size)})
+	}
+	for _, i := range d.Added {
+		header(i.Path, i.Path)
+		hunk("/dev/null", "b/"+i.Path, nil, []string{contentLine(i.Digest, i.Size)})
+	}
+	for _, i := range d.Deleted {
+		header(i.Path, i.Path)
+		hunk("a/"+i.Path, "/dev/null", []string{contentLine(i.Digest, i.Size)}, nil)
+	}
+	for _, i := range d.Renamed {
+		header(i.From, i.To)
+		fmt.Fprintf(&b, "similarity index 100%%\nrename from %s\nrename to %s\n", i.From, i.To)
+	}
+	for _, e := range d.Entries {
+		header(e.Path, e.Path)
+		switch {
+		case e.From != nil && e.To != nil:
+			if gitMode(*e.From) != gitMode(*e.To) {
+				fmt.Fprintf(&b, "old mode %06o\nnew mode %06o\n", gitMode(*e.From), gitMode(*e.To))
+			}
+			if e.From.Target != e.To.Target {
+				hunk("a/"+e.Path, "b/"+e.Path, entryLines(*e.From), entryLines(*e.To))
+			}
+		case e.To != nil:
+			fmt.Fprintf(&b, "new file mode %06o\n", gitMode(*e.To))
+			if lines := entryLines(*e.To); len(lines) > 0 {
+				hunk("/dev/null", "b/"+e.Path, nil, lines)
+			}
+		default:
+			fmt.Fprintf(&b, "deleted file mode %06o\n", gitMode(*e.From))
+			if lines := entryLines(*e.From); len(lines) > 0 {
+				hunk("a/"+e.Path, "/dev/null", lines, nil)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func contentLine(d digest.Digest, size uint64) string {
+	return fmt.Sprintf("%s %d", d, size)
+}
+
+func hunkRange(lines int) string {
+	if lines == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", lines)
+}
+
+// entryLines returns the content lines of e: the target for symlinks, nothing otherwise.
+func entryLines(e Entry) []string {
+	if e.Type == EntryTypeSymlink {
+		return []string{e.Target}
+	}
+	return nil
+}
+
+// gitMode returns the git's representation of e's type and mode.
+func gitMode(e Entry) uint32 {
+	switch e.Type {
+	case EntryTypeSymlink:
+		return 0120000
+	case EntryTypeDir:
+		return 040000 | e.Mode
+	default:
+		return 0100000 | e.Mode
+	}
+}
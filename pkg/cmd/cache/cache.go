@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package cache
+
+import (
+	"github.com/vchain-us/vcn/pkg/cmd/cache/disable"
+	"github.com/vchain-us/vcn/pkg/cmd/cache/enable"
+	"github.com/vchain-us/vcn/pkg/cmd/cache/prune"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the cobra command for `vcn cache`
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local hash cache",
+		Long: `
+Manage the local hash cache.
+
+Once the cache is enabled by 'vcn cache enable', file digests are cached into the vcn
+working directory when processing directories, and reused as long as the file's size,
+modification time, change time (ctime), inode and device are unchanged.
+The cache is disabled by default, and it can be bypassed for a single command by --no-cache.
+`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(enable.NewCommand())
+	cmd.AddCommand(disable.NewCommand())
+	cmd.AddCommand(prune.NewCommand())
+
+	return cmd
+}
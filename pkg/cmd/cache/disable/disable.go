@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package disable
+
+import (
+	"fmt"
+
+	"github.com/vchain-us/vcn/pkg/store"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the cobra command for `vcn cache disable`
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Disable the local hash cache",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE:  runDisable,
+	}
+
+	return cmd
+}
+
+func runDisable(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	store.Config().HashCache = false
+	if err := store.SaveConfig(); err != nil {
+		return err
+	}
+
+	if output, _ := cmd.Flags().GetString("output"); output == "" {
+		fmt.Println("Hash cache disabled.")
+	}
+	return nil
+}
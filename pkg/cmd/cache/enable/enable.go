@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package enable
+
+import (
+	"fmt"
+
+	"github.com/vchain-us/vcn/pkg/store"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the cobra command for `vcn cache enable`
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Enable the local hash cache",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE:  runEnable,
+	}
+
+	return cmd
+}
+
+func runEnable(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	store.Config().HashCache = true
+	if err := store.SaveConfig(); err != nil {
+		return err
+	}
+
+	if output, _ := cmd.Flags().GetString("output"); output == "" {
+		fmt.Println("Hash cache enabled.")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package prune
+
+import (
+	"fmt"
+
+	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
+	"github.com/vchain-us/vcn/pkg/store"
+
+	"github.com/spf13/cobra"
+)
+
+type pruneResult struct {
+	Removed   int `json:"removed" yaml:"removed"`
+	Remaining int `json:"remaining" yaml:"remaining"`
+}
+
+// NewCommand returns the cobra command for `vcn cache prune`
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached digests of files that no longer exist or have changed",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE:  runPrune,
+	}
+
+	cmd.Flags().Bool("all", false, "if set, all cached digests will be removed")
+
+	return cmd
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+
+	c := store.LoadHashCache()
+	r := pruneResult{}
+	if all {
+		r.Removed = c.Clear()
+	} else {
+		r.Removed = c.Prune()
+	}
+	if err := c.Save(); err != nil {
+		return err
+	}
+	r.Remaining = c.Len()
+
+	if output == "" {
+		fmt.Printf("Removed %d cached digests, %d remaining.\n", r.Removed, r.Remaining)
+		return nil
+	}
+	return cli.PrintObjects(output, r)
+}
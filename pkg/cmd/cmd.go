@@ -15,10 +15,12 @@ import (
 	"syscall"
 
 	"github.com/vchain-us/vcn/pkg/cmd/alert"
+	"github.com/vchain-us/vcn/pkg/cmd/cache"
 
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/vchain-us/vcn/pkg/cmd/dashboard"
+	"github.com/vchain-us/vcn/pkg/cmd/diff"
 	"github.com/vchain-us/vcn/pkg/cmd/info"
 	"github.com/vchain-us/vcn/pkg/cmd/inspect"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
@@ -26,6 +28,7 @@ import (
 	"github.com/vchain-us/vcn/pkg/cmd/list"
 	"github.com/vchain-us/vcn/pkg/cmd/login"
 	"github.com/vchain-us/vcn/pkg/cmd/logout"
+	"github.com/vchain-us/vcn/pkg/cmd/proof"
 	"github.com/vchain-us/vcn/pkg/cmd/serve"
 	"github.com/vchain-us/vcn/pkg/cmd/set"
 	"github.com/vchain-us/vcn/pkg/cmd/sign"
@@ -119,6 +122,7 @@ func init() {
 	rootCmd.AddCommand(verify.NewCommand())
 	rootCmd.AddCommand(inspect.NewCommand())
 	rootCmd.AddCommand(list.NewCommand())
+	rootCmd.AddCommand(proof.NewExportCommand())
 
 	// Signing group
 	rootCmd.AddCommand(sign.NewCommand())
@@ -140,6 +144,12 @@ func init() {
 	// Alert comand
 	rootCmd.AddCommand(alert.NewCommand())
 
+	// Cache command
+	rootCmd.AddCommand(cache.NewCommand())
+
+	// Diff command
+	rootCmd.AddCommand(diff.NewCommand())
+
 }
 
 func preExitHook(cmd *cobra.Command, versionCheck bool) {
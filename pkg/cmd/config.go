@@ -14,10 +14,14 @@ import (
 	"os"
 
 	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
+	"github.com/vchain-us/vcn/pkg/extractor/checksums"
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
 	"github.com/vchain-us/vcn/pkg/extractor/docker"
 	"github.com/vchain-us/vcn/pkg/extractor/file"
 	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/k8s"
+	"github.com/vchain-us/vcn/pkg/extractor/plugin"
 
 	"github.com/vchain-us/vcn/pkg/store"
 )
@@ -29,10 +33,15 @@ func initConfig() {
 	extractor.Register("", wildcard.Artifact)
 	extractor.Register(file.Scheme, file.Artifact)
 	extractor.Register(dir.Scheme, dir.Artifact)
+	extractor.Register(archive.Scheme, archive.Artifact)
 	extractor.Register(docker.Scheme, docker.Artifact)
 	extractor.Register(docker.SchemePodman, docker.Artifact)
+	extractor.Register(docker.SchemeOCI, docker.Artifact)
+	extractor.Register(docker.SchemeDockerArchive, docker.Artifact)
 	extractor.Register(git.Scheme, git.Artifact)
 	extractor.Register(wildcard.Scheme, wildcard.Artifact)
+	extractor.Register(checksums.Scheme, checksums.Artifact)
+	extractor.Register(k8s.Scheme, k8s.Artifact)
 
 	// Load config
 	if cfgFile != "" {
@@ -45,4 +54,8 @@ func initConfig() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+
+	// Register external extractors, looked up within the store's plugins dir or PATH
+	// only when an unknown scheme is met (so built-in schemes cannot be overridden)
+	extractor.RegisterFallback(plugin.Resolver(store.PluginsDir()))
 }
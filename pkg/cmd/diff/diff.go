@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package diff
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/k8s"
+	"github.com/vchain-us/vcn/pkg/store"
+)
+
+var hashRegExp = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// NewCommand returns the cobra command for `vcn diff`
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show changes between two directories, manifests or notarized hashes",
+		Long: `
+Show changes between two directories, manifests or notarized hashes.
+
+Changes are listed by path: added, modified, renamed and deleted files and, for
+manifest schema version 2, changed modes, symlinks and empty directories.
+
+Both OLD and NEW must be one of:
+  <directory>
+  dir://<directory>
+  archive://<file>
+  git://<repository>[@<ref>]
+  <manifest file> (e.g. .vcn.manifest.json)
+  <hash> (of a notarized directory, whose manifest is in the local store)
+
+Output can be rendered as text (default), json, yaml or patch (i.e. --output=patch),
+a unified diff where each file is represented by its digest and size.
+`,
+		Example: `
+vcn diff --output=patch dir://release-1.0 dir://release-1.1
+vcn diff --output=json release/.vcn.manifest.json release
+`,
+		RunE: runDiff,
+		Args: cobra.ExactArgs(2),
+	}
+
+	cmd.SetUsageTemplate(
+		strings.Replace(cmd.UsageTemplate(), "{{.UseLine}}", "{{.UseLine}} OLD NEW", 1),
+	)
+
+	cmd.Flags().Bool("gitignore", false, "if set, .gitignore files will be honoured as well as .vcnignore ones (affects directories only)")
+	cmd.Flags().Int("jobs", 0, "number of files to hash concurrently, defaults to the number of CPUs (affects directories only)")
+	cmd.Flags().Uint("manifest-version", bundle.ManifestSchemaVersion, "manifest schema version, version 2 records file modes, symlinks and empty directories too (affects directories only)")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	switch output {
+	case "", "json", "yaml", "patch":
+	default:
+		return fmt.Errorf("output format not supported: %s", output)
+	}
+
+	// manifests are needed, so git commits are always processed by their tree content
+	extractorOptions := []extractor.Option{git.WithTreeContent()}
+
+	gitIgnore, err := cmd.Flags().GetBool("gitignore")
+	if err != nil {
+		return err
+	}
+	if gitIgnore {
+		extractorOptions = append(extractorOptions, dir.WithGitIgnore())
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithJobs(jobs))
+
+	manifestVersion, err := cmd.Flags().GetUint("manifest-version")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithManifestVersion(manifestVersion))
+
+	cmd.SilenceUsage = true
+
+	oldManifest, err := resolveManifest(args[0], extractorOptions...)
+	if err != nil {
+		return err
+	}
+	newManifest, err := resolveManifest(args[1], extractorOptions...)
+	if err != nil {
+		return err
+	}
+
+	d := newManifest.PathDiff(*oldManifest)
+
+	switch output {
+	case "":
+		if d.Equal() {
+			fmt.Printf("No changes from %s to %s\n", args[0], args[1])
+			return nil
+		}
+		fmt.Printf("Changes from %s to %s\n\n%s", args[0], args[1], d.String())
+	case "patch":
+		fmt.Print(d.Patch())
+	default:
+		return cli.PrintObjects(output, d)
+	}
+	return nil
+}
+
+// resolveManifest returns the manifest referenced by arg, that can be an extractor's URI producing a manifest,
+// a directory, a manifest file or the hash of a manifest in the local store.
+func resolveManifest(arg string, options ...extractor.Option) (*bundle.Manifest, error) {
+	if !strings.Contains(arg, "://") {
+		info, err := os.Stat(arg)
+		switch {
+		case err == nil && info.IsDir():
+			arg = dir.Scheme + "://" + arg
+		case err == nil:
+			m, err := bundle.ReadManifest(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a valid manifest: %s", arg, err)
+			}
+			return m, nil
+		case hashRegExp.MatchString(strings.ToLower(arg)):
+			return store.FindManifest(strings.ToLower(arg))
+		default:
+			return nil, err
+		}
+	}
+
+	artifacts, err := extractor.Extract([]string{arg}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) != 1 {
+		return nil, fmt.Errorf("unable to process the input asset provided: %s", arg)
+	}
+	for _, metadata := range []func(a api.Artifact) (*bundle.Manifest, string){dir.Metadata, archive.Metadata, git.Metadata, k8s.Metadata} {
+		if m, _ := metadata(*artifacts[0]); m != nil {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no manifest available for %s", arg)
+}
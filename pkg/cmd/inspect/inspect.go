@@ -20,8 +20,12 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
 	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/wildcard"
 	"github.com/vchain-us/vcn/pkg/store"
 )
 
@@ -90,6 +94,12 @@ vcn inspect document.pdf --signerID CygBE_zb8XnprkkO6ncIrbbwYoUq5T1zfyEF6DhqcAI=
 
 	cmd.Flags().String("hash", "", "specify a hash to inspect, if set no ARG can be used")
 	cmd.Flags().Bool("extract-only", false, "if set, print only locally extracted info")
+	cmd.Flags().Bool("archive", false, "if set, a tar, tar.gz or zip file passed as ARG will be processed by its content (as archive://)")
+	cmd.Flags().Bool("git-tree", false, "if set, a git commit will be hashed by its tree content, matching dir:// of the same checkout only if a committed .vcnignore excludes .git (affects git:// only)")
+	cmd.Flags().Bool("gitignore", false, "if set, .gitignore files will be honoured as well as .vcnignore ones (affects dir:// only)")
+	cmd.Flags().Int("jobs", 0, "number of files to hash concurrently, defaults to the number of CPUs (affects dir:// and wildcard usage only)")
+	cmd.Flags().Bool("no-cache", false, "if set, the local hash cache is not used even if enabled by 'vcn cache enable' (affects dir:// only)")
+	cmd.Flags().Uint("manifest-version", bundle.ManifestSchemaVersion, "manifest schema version, version 2 records file modes, symlinks and empty directories too (affects dir:// only)")
 	// ledger compliance flags
 	cmd.Flags().String("lc-host", "", meta.VcnLcHostFlagDesc)
 	cmd.Flags().String("lc-port", "443", meta.VcnLcPortFlagDesc)
@@ -126,13 +136,64 @@ func runInspect(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+
+	// default extractors options
+	extractorOptions := []extractor.Option{}
+
+	archive, err := cmd.Flags().GetBool("archive")
+	if err != nil {
+		return err
+	}
+	if archive {
+		extractorOptions = append(extractorOptions, wildcard.WithArchive())
+	}
+
+	gitTree, err := cmd.Flags().GetBool("git-tree")
+	if err != nil {
+		return err
+	}
+	if gitTree {
+		extractorOptions = append(extractorOptions, git.WithTreeContent())
+	}
+
+	gitIgnore, err := cmd.Flags().GetBool("gitignore")
+	if err != nil {
+		return err
+	}
+	if gitIgnore {
+		extractorOptions = append(extractorOptions, dir.WithGitIgnore(), wildcard.WithGitIgnore())
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithJobs(jobs), wildcard.WithJobs(jobs))
+
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		return err
+	}
+	if store.HashCacheEnabled() && !noCache {
+		hashCache := store.LoadHashCache()
+		// cache is optional, so errors are ignored
+		defer hashCache.Save()
+		extractorOptions = append(extractorOptions, dir.WithHashCache(hashCache), wildcard.WithHashCache(hashCache))
+	}
+
+	manifestVersion, err := cmd.Flags().GetUint("manifest-version")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithManifestVersion(manifestVersion), wildcard.WithManifestVersion(manifestVersion))
+
 	cmd.SilenceUsage = true
 
 	if hash == "" {
 		if len(args) < 1 {
 			return fmt.Errorf("no argument")
 		}
-		if hash, err = extractInfo(args[0], output); err != nil {
+		if hash, err = extractInfo(args[0], output, extractorOptions...); err != nil {
 			return err
 		}
 		if output == "" {
@@ -223,8 +284,8 @@ func runInspect(cmd *cobra.Command, args []string) error {
 	return inspect(hash, u, output)
 }
 
-func extractInfo(arg string, output string) (hash string, err error) {
-	a, err := extractor.Extract([]string{arg})
+func extractInfo(arg string, output string, options ...extractor.Option) (hash string, err error) {
+	a, err := extractor.Extract([]string{arg}, options...)
 	if err != nil {
 		return "", err
 	}
@@ -100,6 +100,15 @@ func WriteLcResultTo(r *types.LcResult, out io.Writer) (n int64, err error) {
 						}
 					}
 				}
+			case "Valid from", "Valid until":
+				if t, ok := f.Interface().(*time.Time); ok && t != nil {
+					value = t.Format(time.UnixDate)
+					// the bound the current time is outside of is highlighted
+					now := time.Now()
+					if (key == "Valid from" && now.Before(*t)) || (key == "Valid until" && !now.Before(*t)) {
+						value = color.New(meta.StyleError()).Sprintf(value)
+					}
+				}
 			case "Attachments":
 				if attachments, ok := f.Interface().([]api.Attachment); ok {
 					for _, attach := range attachments {
@@ -134,6 +143,33 @@ func WriteLcResultTo(r *types.LcResult, out io.Writer) (n int64, err error) {
 		}
 	}
 
+	if r.TxID != 0 {
+		err = printf("TxID:\t%d\n", r.TxID)
+		if err != nil {
+			return
+		}
+	}
+
+	if r.MinSigners > 0 {
+		err = printf("Trusted signers:\t%d of %d required\n", len(r.TrustedSigners), r.MinSigners)
+		if err != nil {
+			return
+		}
+		for _, id := range r.TrustedSigners {
+			err = printf("\t- %s\n", id)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if r.Policy != nil {
+		err = writePolicyReport(r.Policy, printf)
+		if err != nil {
+			return
+		}
+	}
+
 	// here extra data when --verbose flag is provided
 	if r.Verbose != nil {
 		err = printf("\nAdditional details:\n")
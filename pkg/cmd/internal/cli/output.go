@@ -26,6 +26,7 @@ import (
 	"github.com/vchain-us/vcn/pkg/api"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
 	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/policy"
 )
 
 func WriteResultTo(r *types.Result, out io.Writer) (n int64, err error) {
@@ -110,6 +111,13 @@ func WriteResultTo(r *types.Result, out io.Writer) (n int64, err error) {
 		}
 	}
 
+	if r.Policy != nil {
+		err = writePolicyReport(r.Policy, printf)
+		if err != nil {
+			return
+		}
+	}
+
 	for _, e := range r.Errors {
 		err = printf("Error:\t%s\n", color.New(meta.StyleError()).Sprintf(e.Error()))
 		if err != nil {
@@ -120,6 +128,22 @@ func WriteResultTo(r *types.Result, out io.Writer) (n int64, err error) {
 	return n, w.Flush()
 }
 
+// writePolicyReport prints the outcome of a policy evaluation, followed by the failed rules, if any.
+func writePolicyReport(r *policy.Report, printf func(format string, a ...interface{}) error) error {
+	if r.Passed {
+		return printf("Policy:\t%s\n", color.New(meta.StyleSuccess()).Sprintf("PASSED"))
+	}
+	if err := printf("Policy:\t%s\n", color.New(meta.StyleError()).Sprintf("FAILED")); err != nil {
+		return err
+	}
+	for _, v := range r.Violations {
+		if err := printf("\t- %s\n", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func Print(output string, r *types.Result) error {
 	switch output {
 	case "", "attachments":
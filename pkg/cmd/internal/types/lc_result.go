@@ -10,12 +10,17 @@ package types
 
 import (
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/policy"
 )
 
 type LcResult struct {
 	api.LcArtifact `yaml:",inline"`
 	Verified       bool           `json:"verified" yaml:"verified" vcn:"Verified"`
 	Verbose        *LcVerboseInfo `yaml:",inline" vcn:"Verbose"`
+	TxID           uint64         `json:"txID,omitempty" yaml:"txID,omitempty"`
+	TrustedSigners []string       `json:"trustedSigners,omitempty" yaml:"trustedSigners,omitempty"`
+	MinSigners     int            `json:"minSigners,omitempty" yaml:"minSigners,omitempty"`
+	Policy         *policy.Report `json:"policy,omitempty" yaml:"policy,omitempty"`
 	Errors         []error        `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
@@ -29,6 +34,15 @@ func (r *LcResult) AddError(err error) {
 	r.Errors = append(r.Errors, err)
 }
 
+// SetSigners records the SignerIDs with a trusted notarization, when at least min of them are required.
+func (r *LcResult) SetSigners(trusted []string, min int) {
+	if min == 0 {
+		return
+	}
+	r.TrustedSigners = trusted
+	r.MinSigners = min
+}
+
 func NewLcResult(lca *api.LcArtifact, verified bool, verbose *LcVerboseInfo) *LcResult {
 
 	var r LcResult
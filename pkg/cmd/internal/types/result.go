@@ -10,11 +10,13 @@ package types
 
 import (
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/policy"
 )
 
 type Result struct {
 	api.ArtifactResponse `yaml:",inline"`
 	Verification         *api.BlockchainVerification `json:"verification" yaml:"verification"`
+	Policy               *policy.Report              `json:"policy,omitempty" yaml:"policy,omitempty"`
 	Errors               []error                     `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
@@ -34,16 +36,16 @@ func NewResult(a *api.Artifact, ar *api.ArtifactResponse, v *api.BlockchainVerif
 
 	switch true {
 	case ar != nil:
-		r = Result{*ar, vv, nil}
+		r = Result{ArtifactResponse: *ar, Verification: vv}
 	case a != nil:
-		r = Result{api.ArtifactResponse{
+		r = Result{ArtifactResponse: api.ArtifactResponse{
 			Name:        a.Name,
 			Kind:        a.Kind,
 			Hash:        a.Hash,
 			Size:        a.Size,
 			ContentType: a.ContentType,
 			Metadata:    a.Metadata,
-		}, vv, nil}
+		}, Verification: vv}
 	default:
 		r = Result{}
 		r.Verification = vv
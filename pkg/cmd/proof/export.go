@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package proof
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/wildcard"
+	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/store"
+)
+
+// NewExportCommand returns the cobra command for `vcn export-proof`
+func NewExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-proof",
+		Short: "Export a proof bundle to authenticate an asset offline",
+		Long: `
+Export a proof bundle to authenticate an asset offline.
+
+The proof bundle is a self-contained JSON file holding the notarization of the
+asset on CodeNotary Immutable Ledger, its inclusion and dual proofs, and the
+ledger state they have been verified against. The asset can then be
+authenticated without any network access by:
+
+  vcn authenticate --proof <bundle file> --lc-signing-pub-key <key file> ARG
+
+where the public signing key of the ledger server authenticates the ledger
+state the proofs lead to.
+
+Note that the notarization's timestamp and the API key's revocation time are
+the ones reported by the ledger when the bundle was exported.
+
+Environment variables:
+VCN_LC_HOST=
+VCN_LC_PORT=
+VCN_LC_CERT=
+VCN_LC_SKIP_TLS_VERIFY=false
+VCN_LC_NO_TLS=false
+VCN_LC_API_KEY=
+VCN_LC_LEDGER=
+`,
+		Example: `
+vcn export-proof --file bundle.json docker://hello-world
+vcn export-proof --signerID CygBE_zb8XnprkkO6ncIrbbwYoUq5T1zfyEF6DhqcAI= document.pdf > bundle.json
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: runExport,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if hash, _ := cmd.Flags().GetString("hash"); hash != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("cannot use ARG with --hash")
+				}
+				return nil
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+	}
+
+	cmd.SetUsageTemplate(
+		strings.Replace(cmd.UsageTemplate(), "{{.UseLine}}", "{{.UseLine}} ARG", 1),
+	)
+
+	cmd.Flags().StringP("file", "f", "", "write the proof bundle to the given file, instead of the standard output")
+	cmd.Flags().String("hash", "", "specify the hash of the asset, if set no ARG can be used")
+	cmd.Flags().StringP("signerID", "s", "", "export the notarization by the given SignerID, instead of the current user's one")
+	cmd.Flags().Bool("archive", false, "if set, a tar, tar.gz or zip file passed as ARG will be processed by its content (as archive://)")
+	cmd.Flags().Bool("git-tree", false, "if set, a git commit will be hashed by its tree content, matching dir:// of the same checkout (affects git:// only)")
+	cmd.Flags().Bool("gitignore", false, "if set, .gitignore files will be honoured as well as .vcnignore ones (affects dir:// only)")
+	cmd.Flags().Uint("manifest-version", bundle.ManifestSchemaVersion, "manifest schema version, version 2 records file modes, symlinks and empty directories too (affects dir:// only)")
+	// ledger compliance flags
+	cmd.Flags().String("lc-host", "", meta.VcnLcHostFlagDesc)
+	cmd.Flags().String("lc-port", "443", meta.VcnLcPortFlagDesc)
+	cmd.Flags().String("lc-cert", "", meta.VcnLcCertPathDesc)
+	cmd.Flags().Bool("lc-skip-tls-verify", false, meta.VcnLcSkipTlsVerifyDesc)
+	cmd.Flags().Bool("lc-no-tls", false, meta.VcnLcNoTlsDesc)
+	cmd.Flags().String("lc-api-key", "", meta.VcnLcApiKeyDesc)
+	cmd.Flags().String("lc-ledger", "", meta.VcnLcLedgerDesc)
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	hash, err := cmd.Flags().GetString("hash")
+	if err != nil {
+		return err
+	}
+	hash = strings.ToLower(hash)
+	signerID, err := cmd.Flags().GetString("signerID")
+	if err != nil {
+		return err
+	}
+
+	// default extractors options
+	extractorOptions := []extractor.Option{}
+
+	archive, err := cmd.Flags().GetBool("archive")
+	if err != nil {
+		return err
+	}
+	if archive {
+		extractorOptions = append(extractorOptions, wildcard.WithArchive())
+	}
+
+	gitTree, err := cmd.Flags().GetBool("git-tree")
+	if err != nil {
+		return err
+	}
+	if gitTree {
+		extractorOptions = append(extractorOptions, git.WithTreeContent())
+	}
+
+	gitIgnore, err := cmd.Flags().GetBool("gitignore")
+	if err != nil {
+		return err
+	}
+	if gitIgnore {
+		extractorOptions = append(extractorOptions, dir.WithGitIgnore(), wildcard.WithGitIgnore())
+	}
+
+	manifestVersion, err := cmd.Flags().GetUint("manifest-version")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithManifestVersion(manifestVersion), wildcard.WithManifestVersion(manifestVersion))
+
+	cmd.SilenceUsage = true
+
+	if hash == "" {
+		artifacts, err := extractor.Extract(args, extractorOptions...)
+		if err != nil {
+			return err
+		}
+		if len(artifacts) != 1 {
+			return fmt.Errorf("a proof bundle can be exported for exactly one asset, %d found", len(artifacts))
+		}
+		hash = artifacts[0].Hash
+	}
+
+	lcHost := viper.GetString("lc-host")
+	lcPort := viper.GetString("lc-port")
+	lcCert := viper.GetString("lc-cert")
+	skipTlsVerify := viper.GetBool("lc-skip-tls-verify")
+	noTls := viper.GetBool("lc-no-tls")
+	lcApiKey := viper.GetString("lc-api-key")
+	lcLedger := viper.GetString("lc-ledger")
+
+	//check if an lcUser is present inside the context
+	var lcUser *api.LcUser
+	uif, err := api.GetUserFromContext(store.Config().CurrentContext, lcApiKey, lcLedger)
+	if err != nil {
+		return err
+	}
+	if lctmp, ok := uif.(*api.LcUser); ok {
+		lcUser = lctmp
+	}
+
+	// use credentials if host is at least host is provided
+	if lcHost != "" && lcApiKey != "" {
+		lcUser, err = api.NewLcUser(lcApiKey, lcLedger, lcHost, lcPort, lcCert, skipTlsVerify, noTls)
+		if err != nil {
+			return err
+		}
+		// Store the new config
+		if err := store.SaveConfig(); err != nil {
+			return err
+		}
+	}
+
+	if lcUser == nil {
+		return fmt.Errorf("proof bundles are supported by CodeNotary Immutable Ledger only\nProceed by authenticating yourself using <vcn login>")
+	}
+	if err := lcUser.Client.Connect(); err != nil {
+		return err
+	}
+
+	proof, err := lcUser.ExportProof(hash, signerID)
+	switch err {
+	case nil:
+	case api.ErrNotFound:
+		return fmt.Errorf("%s was not notarized", hash)
+	case api.ErrNotVerified:
+		return fmt.Errorf("the ledger is compromised. Please contact the CodeNotary Immutable Ledger administrators")
+	default:
+		return err
+	}
+
+	b, err := json.MarshalIndent(proof, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		fmt.Println(string(b))
+		return nil
+	}
+	if err := ioutil.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	if output == "" {
+		fmt.Printf("Proof bundle of %s written to %s\n", hash, path)
+	}
+	return nil
+}
@@ -10,15 +10,19 @@ package sign
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/vchain-us/vcn/pkg/store"
 
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
 	"github.com/vchain-us/vcn/pkg/extractor/file"
 	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/k8s"
 	"github.com/vchain-us/vcn/pkg/uri"
 
 	"github.com/vchain-us/vcn/pkg/api"
@@ -28,6 +32,23 @@ type alertOptions struct {
 	arg   string
 	name  string
 	email string
+	// extractor options to be kept by the alert as URI's query parameters, by artifact kind
+	query map[string]url.Values
+}
+
+// extractorQuery returns the extractor options set by flags as URI's query parameters, by artifact kind.
+func extractorQuery(gitTree bool, gitIgnore bool, manifestVersion uint) map[string]url.Values {
+	q := map[string]url.Values{
+		dir.Scheme: {"manifest-version": {strconv.FormatUint(uint64(manifestVersion), 10)}},
+		git.Scheme: {},
+	}
+	if gitIgnore {
+		q[dir.Scheme].Set("gitignore", "true")
+	}
+	if gitTree {
+		q[git.Scheme].Set("tree", "true")
+	}
+	return q
 }
 
 func handleAlert(opts *alertOptions, u api.User, a api.Artifact, v api.BlockchainVerification, output string) error {
@@ -48,15 +69,37 @@ func handleAlert(opts *alertOptions, u api.User, a api.Artifact, v api.Blockchai
 		fallthrough
 	case dir.Scheme:
 		fallthrough
+	case archive.Scheme:
+		fallthrough
+	case k8s.Scheme:
+		fallthrough
 	case git.Scheme:
 		absPath, err := filepath.Abs(strings.TrimPrefix(aURI.Opaque, "//"))
 		if err != nil {
 			return err
 		}
+		q := opts.query[a.Kind]
+		if aURI.Scheme == "" && (len(q) > 0 || a.Kind == archive.Scheme) {
+			// an existing path (i.e. not a pattern) is bound to the artifact kind,
+			// so that both the kind and the query are kept
+			if _, err := os.Stat(absPath); err == nil {
+				aURI.Scheme = a.Kind
+			}
+		}
 		if aURI.Scheme == "" {
 			aURI.Opaque = absPath
 		} else {
 			aURI.Opaque = "//" + absPath
+			if len(q) > 0 {
+				// query parameters given by the argument take precedence
+				values := aURI.Query()
+				for k, v := range q {
+					if _, ok := values[k]; !ok {
+						values[k] = v
+					}
+				}
+				aURI.RawQuery = values.Encode()
+			}
 		}
 		opts.arg = aURI.String()
 		m["path"] = absPath
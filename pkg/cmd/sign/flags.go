@@ -10,11 +10,22 @@ package sign
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vchain-us/vcn/pkg/meta"
 )
 
 func noArgsWhenHashOrPipe(cmd *cobra.Command, args []string) error {
+	if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+		if hash, _ := cmd.Flags().GetString("hash"); hash != "" {
+			return fmt.Errorf("cannot use both --stdin and --hash")
+		}
+		if len(args) > 0 {
+			return fmt.Errorf("cannot use ARG(s) with --stdin")
+		}
+		return nil
+	}
 	if hash, _ := cmd.Flags().GetString("hash"); hash != "" {
 		if len(args) > 0 {
 			return fmt.Errorf("cannot use ARG(s) with --hash")
@@ -26,3 +37,24 @@ func noArgsWhenHashOrPipe(cmd *cobra.Command, args []string) error {
 	}
 	return cobra.ExactArgs(1)(cmd, args)
 }
+
+// parseValidityFlag parses the value of --valid-from or --valid-until, that can be either
+// a RFC3339 or meta.DateShortForm date, or a duration relative to now (e.g. 720h, -1h).
+// An empty value means no bound, so nil is returned.
+func parseValidityFlag(name, value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation(meta.DateShortForm, value, time.Local); err == nil {
+		return &t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		t := now.Add(d)
+		return &t, nil
+	}
+	return nil, fmt.Errorf("invalid --%s: %s is neither a date (e.g. %s or %s) nor a duration (e.g. 720h)",
+		name, value, now.Format(time.RFC3339), now.Format(meta.DateShortForm))
+}
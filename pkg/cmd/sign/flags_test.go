@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidityFlag(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	v, err := parseValidityFlag("valid-until", "", now)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = parseValidityFlag("valid-until", "2021-02-01T00:00:00Z", now)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC).Equal(*v))
+
+	v, err = parseValidityFlag("valid-until", "2021/2/1-10:30:00", now)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2021, 2, 1, 10, 30, 0, 0, time.Local).Equal(*v))
+
+	// relative to now
+	v, err = parseValidityFlag("valid-until", "720h", now)
+	assert.NoError(t, err)
+	assert.True(t, now.Add(720*time.Hour).Equal(*v))
+	v, err = parseValidityFlag("valid-from", "-1h", now)
+	assert.NoError(t, err)
+	assert.True(t, now.Add(-time.Hour).Equal(*v))
+
+	_, err = parseValidityFlag("valid-until", "next week", now)
+	assert.Error(t, err)
+}
@@ -15,7 +15,11 @@ import (
 
 	"github.com/vchain-us/vcn/pkg/api"
 	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/k8s"
 )
 
 type hook struct {
@@ -28,6 +32,10 @@ func newHook(a *api.Artifact) *hook {
 			a: a.Copy(),
 		}
 		dir.RemoveMetadata(a)
+		archive.RemoveMetadata(a)
+		git.RemoveMetadata(a)
+		k8s.RemoveMetadata(a)
+		file.RemoveMetadata(a)
 		return &h
 	}
 	return nil
@@ -35,28 +43,32 @@ func newHook(a *api.Artifact) *hook {
 
 func (h *hook) finalize(v *api.BlockchainVerification, readOnly bool) error {
 	if h != nil && !v.Unknown() {
-		manifest, path := dir.Metadata(h.a)
-		if manifest != nil && path != "" {
-			// manifest is optional, we can ignore errors
-			store.SaveManifest(h.a.Kind, path, *manifest)
-			if !readOnly {
-				bundle.WriteManifest(*manifest, filepath.Join(path, bundle.ManifestFilename))
-			}
-		}
+		h.saveManifest(readOnly)
 	}
 	return nil
 }
 
 func (h *hook) finalizeWithoutVerification(readOnly bool) error {
 	if h != nil {
-		manifest, path := dir.Metadata(h.a)
-		if manifest != nil && path != "" {
-			// manifest is optional, we can ignore errors
+		h.saveManifest(readOnly)
+	}
+	return nil
+}
+
+// saveManifest stores the artifact's manifest, if any.
+// Manifest is optional, so errors are ignored.
+func (h *hook) saveManifest(readOnly bool) {
+	manifest, path := dir.Metadata(h.a)
+	if manifest != nil && path != "" {
+		store.SaveManifest(h.a.Kind, path, *manifest)
+		if !readOnly {
+			bundle.WriteManifest(*manifest, filepath.Join(path, bundle.ManifestFilename))
+		}
+	}
+	// archives, git trees and Kubernetes manifests cannot hold the manifest, so it's only saved into the store
+	for _, metadata := range []func(api.Artifact) (*bundle.Manifest, string){archive.Metadata, git.Metadata, k8s.Metadata} {
+		if manifest, path := metadata(h.a); manifest != nil && path != "" {
 			store.SaveManifest(h.a.Kind, path, *manifest)
-			if !readOnly {
-				bundle.WriteManifest(*manifest, filepath.Join(path, bundle.ManifestFilename))
-			}
 		}
 	}
-	return nil
 }
@@ -2,6 +2,7 @@ package sign
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/spin"
 	"github.com/fatih/color"
@@ -12,7 +13,7 @@ import (
 	"github.com/vchain-us/vcn/pkg/meta"
 )
 
-func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output string, name string, metadata map[string]interface{}, attach []string, verbose bool) error {
+func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output string, name string, metadata map[string]interface{}, attach []string, validFrom, validUntil *time.Time, verbose bool, atomic bool) error {
 
 	if output == "" {
 		color.Set(meta.StyleAffordance())
@@ -22,6 +23,10 @@ func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output
 		fmt.Println()
 	}
 
+	if atomic {
+		return lcSignAtomic(u, artifacts, state, output, name, metadata, attach, validFrom, validUntil, verbose)
+	}
+
 	s := spin.New("%s Notarization in progress...")
 	s.Set(spin.Spin1)
 
@@ -31,16 +36,14 @@ func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output
 		bar = progressbar.Default(int64(lenArtifacts))
 	}
 
-	var hook *hook
-	if len(artifacts) == 1 {
-		hook = newHook(artifacts[0])
-		// Override the asset's name, if provided by --name
-		if name != "" {
-			artifacts[0].Name = name
-		}
+	// Override the asset's name, if provided by --name
+	if len(artifacts) == 1 && name != "" {
+		artifacts[0].Name = name
 	}
 
 	for _, a := range artifacts {
+		// The hook strips the local metadata (e.g. manifests and paths), that must never be notarized
+		hook := newHook(a)
 		// Copy user provided custom attributes
 		a.Metadata.SetValues(metadata)
 
@@ -49,6 +52,7 @@ func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output
 			*a,
 			api.LcSignWithStatus(state),
 			api.LcSignWithAttachments(attach),
+			api.LcSignWithValidity(validFrom, validUntil),
 		)
 		if err != nil {
 			if err == api.ErrNotVerified {
@@ -62,17 +66,11 @@ func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output
 		}
 
 		// writingManifest
-		if hook != nil && len(artifacts) == 1 {
-			err = hook.finalizeWithoutVerification(false)
-			if err != nil {
-				return cli.PrintWarning(output, err.Error())
-			}
-		}
-
-		if err != nil {
+		if err := hook.finalizeWithoutVerification(false); err != nil {
 			return cli.PrintWarning(output, err.Error())
 		}
-		if output == "" && lenArtifacts == 0 {
+
+		if output == "" && lenArtifacts == 1 {
 			fmt.Println()
 		}
 
@@ -113,3 +111,81 @@ func LcSign(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output
 	}
 	return nil
 }
+
+// lcSignAtomic notarizes all artifacts within a single ledger transaction (see api.LcUser.SignBatch),
+// then reports every artifact along with the transaction ID.
+func lcSignAtomic(u *api.LcUser, artifacts []*api.Artifact, state meta.Status, output string, name string, metadata map[string]interface{}, attach []string, validFrom, validUntil *time.Time, verbose bool) error {
+	// Override the asset's name, if provided by --name
+	if len(artifacts) == 1 && name != "" {
+		artifacts[0].Name = name
+	}
+
+	hooks := make([]*hook, len(artifacts))
+	batch := make([]api.Artifact, len(artifacts))
+	for i, a := range artifacts {
+		// The hook strips the local metadata (e.g. manifests and paths), that must never be notarized
+		hooks[i] = newHook(a)
+		// Copy user provided custom attributes
+		a.Metadata.SetValues(metadata)
+		batch[i] = *a
+	}
+
+	txID, err := u.SignBatch(
+		batch,
+		api.LcSignWithStatus(state),
+		api.LcSignWithAttachments(attach),
+		api.LcSignWithValidity(validFrom, validUntil),
+	)
+	if err != nil {
+		return err
+	}
+
+	// writingManifest
+	for _, hook := range hooks {
+		if err := hook.finalizeWithoutVerification(false); err != nil {
+			return cli.PrintWarning(output, err.Error())
+		}
+	}
+
+	results := make([]*types.LcResult, len(artifacts))
+	for i, a := range artifacts {
+		artifact, verified, err := u.LoadArtifact(a.Hash, "", "", txID, nil)
+		if err != nil {
+			if err == api.ErrNotVerified {
+				color.Set(meta.StyleError())
+				fmt.Println("the ledger is compromised. Please contact the CodeNotary Immutable Ledger administrators")
+				color.Unset()
+				fmt.Println()
+				return nil
+			}
+			return cli.PrintWarning(output, err.Error())
+		}
+		var verbInfos *types.LcVerboseInfo
+		if verbose {
+			verbInfos = &types.LcVerboseInfo{
+				LedgerName: artifact.Ledger,
+				LocalSID:   api.GetSignerIDByApiKey(u.Client.ApiKey),
+				ApiKey:     u.Client.ApiKey,
+			}
+		}
+		results[i] = types.NewLcResult(artifact, verified, verbInfos)
+		results[i].TxID = txID
+	}
+
+	switch {
+	case output != "":
+		return cli.PrintLcSlice(output, results)
+	case len(results) == 1:
+		return cli.PrintLc(output, results[0])
+	}
+
+	for _, r := range results {
+		fmt.Printf("%s\t%s\ttx %d\n", r.Hash, r.Name, r.TxID)
+	}
+	fmt.Println()
+	color.Set(meta.StyleSuccess())
+	fmt.Printf("notarized %d items within transaction %d", len(results), txID)
+	color.Unset()
+	fmt.Println()
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/vchain-us/vcn/pkg/cicontext"
 
@@ -20,6 +21,8 @@ import (
 	"github.com/vchain-us/vcn/pkg/extractor/wildcard"
 
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
 
 	"github.com/fatih/color"
 
@@ -27,6 +30,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vchain-us/vcn/internal/assert"
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
 	"github.com/vchain-us/vcn/pkg/extractor"
@@ -47,10 +51,24 @@ ARG must be one of:
   directory
   file://<file>
   dir://<directory>
-  git://<repository>
+  archive://<file>
+  git://<repository>[@<ref>]
+  git://<repository>#range=<from>..<to>
   docker://<image>
   podman://<image>
+  oci://<directory>[:<ref>]
+  docker-archive://<file>[:<repo:tag>]
   wildcard://"*"
+  checksums://<file>
+  k8s://<file-or-directory>
+
+With checksums://, each hash listed within a sha256sum (GNU or BSD format)
+file is notarized by using the listed filename as the asset name. The listed
+files are not needed.
+
+With k8s://, the Kubernetes objects defined within YAML (or JSON) files are
+canonicalized, so that re-formatting does not change the hash. Use
+k8s://<path>?split=true to notarize each object separately.
 `
 
 // NewCommand returns the cobra command for `vcn sign`
@@ -84,6 +102,10 @@ Note that your assets will not be uploaded. They will be processed locally.
 Assets are referenced by passed ARG with notarization only accepting
 1 ARG at a time.
 
+On CodeNotary Immutable Ledger, --valid-from and --valid-until set a validity
+window for the notarization, as dates or as durations from now (e.g. 720h):
+outside of it, the asset is authenticated as EXPIRED.
+
 Pipe mode:
 If '-' is provided (echo my-file | vcn n -) stdin is read and parsed. Only pipe ARGs are processed.
 
@@ -123,7 +145,8 @@ VCN_LC_API_KEY=
 		Args: noArgsWhenHashOrPipe,
 		Example: `vcn notarize my-file"
 vcn notarize -r "*.md"
-echo my-file | vcn n -`,
+echo my-file | vcn n -
+curl -s https://example.com/artifact.bin | vcn n --stdin --name artifact.bin`,
 	}
 
 	cmd.Flags().VarP(make(mapOpts), "attr", "a", "add user defined attributes (repeat --attr for multiple entries)")
@@ -131,9 +154,17 @@ echo my-file | vcn n -`,
 	cmd.Flags().StringP("name", "n", "", "set the asset name")
 	cmd.Flags().BoolP("public", "p", false, "when notarized as public, the asset name and metadata will be visible to everyone")
 	cmd.Flags().String("hash", "", "specify the hash instead of using an asset, if set no ARG(s) can be used")
+	cmd.Flags().Bool("stdin", false, "if set, the data streamed on stdin will be notarized as a file (--name is required), no ARG(s) can be used")
 	cmd.Flags().Bool("no-ignore-file", false, "if set, .vcnignore will be not written inside the targeted dir (affects dir:// only)")
 	cmd.Flags().Bool("read-only", false, "if set, no files will be written into the targeted dir (affects dir:// only)")
 	cmd.Flags().BoolP("recursive", "r", false, "if set, wildcard usage will walk inside subdirectories of provided path")
+	cmd.Flags().Bool("archive", false, "if set, wildcard usage will process tar, tar.gz and zip files by their content (as archive://)")
+	cmd.Flags().Bool("git-tree", false, "if set, git commits will be hashed by their tree content, matching dir:// of the same checkout only if a committed .vcnignore excludes .git (affects git:// only)")
+	cmd.Flags().Bool("gitignore", false, "if set, .gitignore files will be honoured as well as .vcnignore ones (affects dir:// only)")
+	cmd.Flags().Int("jobs", 0, "number of files to hash concurrently, defaults to the number of CPUs (affects dir:// and wildcard usage only)")
+	cmd.Flags().Bool("no-cache", false, "if set, the local hash cache is not used even if enabled by 'vcn cache enable' (affects dir:// only)")
+	cmd.Flags().Uint("manifest-version", bundle.ManifestSchemaVersion, "manifest schema version, version 2 records file modes, symlinks and empty directories too (affects dir:// only)")
+	cmd.Flags().StringSlice("digest", nil, "additional digest algorithms to be computed and notarized along with sha256, e.g. sha512,sha3-256 (affects files only)")
 	cmd.Flags().String("lc-host", "", meta.VcnLcHostFlagDesc)
 	cmd.Flags().String("lc-port", "443", meta.VcnLcPortFlagDesc)
 	cmd.Flags().String("lc-cert", "", meta.VcnLcCertPathDesc)
@@ -141,6 +172,9 @@ echo my-file | vcn n -`,
 	cmd.Flags().Bool("lc-no-tls", false, meta.VcnLcNoTlsDesc)
 	cmd.Flags().String("lc-api-key", "", meta.VcnLcApiKeyDesc)
 	cmd.Flags().StringArray("attach", nil, meta.VcnLcAttachDesc)
+	cmd.Flags().String("valid-from", "", "if set, the asset is trusted from the given date on (RFC3339, "+meta.DateShortForm+", or a duration from now, e.g. 24h) (CodeNotary Immutable Ledger only)")
+	cmd.Flags().String("valid-until", "", "if set, the asset is trusted until the given date, then it is reported as EXPIRED (RFC3339, "+meta.DateShortForm+", or a duration from now, e.g. 720h) (CodeNotary Immutable Ledger only)")
+	cmd.Flags().Bool("atomic", false, "if set, all assets are notarized within a single ledger transaction, so that either all or none of them are notarized. Assets exceeding the limits of a single transaction are refused (CodeNotary Immutable Ledger only)")
 	cmd.SetUsageTemplate(
 		strings.Replace(cmd.UsageTemplate(), "{{.UseLine}}", "{{.UseLine}} ARG", 1),
 	)
@@ -175,6 +209,61 @@ func runSignWithState(cmd *cobra.Command, args []string, state meta.Status) erro
 	if recursive {
 		extractorOptions = append(extractorOptions, wildcard.WithRecursive())
 	}
+
+	archive, err := cmd.Flags().GetBool("archive")
+	if err != nil {
+		return err
+	}
+	if archive {
+		extractorOptions = append(extractorOptions, wildcard.WithArchive())
+	}
+
+	gitTree, err := cmd.Flags().GetBool("git-tree")
+	if err != nil {
+		return err
+	}
+	if gitTree {
+		extractorOptions = append(extractorOptions, git.WithTreeContent())
+	}
+
+	gitIgnore, err := cmd.Flags().GetBool("gitignore")
+	if err != nil {
+		return err
+	}
+	if gitIgnore {
+		extractorOptions = append(extractorOptions, dir.WithGitIgnore(), wildcard.WithGitIgnore())
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithJobs(jobs), wildcard.WithJobs(jobs))
+
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		return err
+	}
+	if store.HashCacheEnabled() && !noCache {
+		hashCache := store.LoadHashCache()
+		// cache is optional, so errors are ignored
+		defer hashCache.Save()
+		extractorOptions = append(extractorOptions, dir.WithHashCache(hashCache), wildcard.WithHashCache(hashCache))
+	}
+
+	manifestVersion, err := cmd.Flags().GetUint("manifest-version")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithManifestVersion(manifestVersion), wildcard.WithManifestVersion(manifestVersion))
+
+	digests, err := cmd.Flags().GetStringSlice("digest")
+	if err != nil {
+		return err
+	}
+	if len(digests) > 0 {
+		extractorOptions = append(extractorOptions, file.WithDigests(digests...), wildcard.WithDigests(digests...))
+	}
 	var alert *alertOptions
 	if hasCreateAlert := cmd.Flags().Lookup("create-alert"); hasCreateAlert != nil {
 		createAlert, err := cmd.Flags().GetBool("create-alert")
@@ -183,7 +272,8 @@ func runSignWithState(cmd *cobra.Command, args []string, state meta.Status) erro
 		}
 		if createAlert {
 			alert = &alertOptions{
-				arg: args[0],
+				arg:   args[0],
+				query: extractorQuery(gitTree, gitIgnore, manifestVersion),
 			}
 			alert.name, _ = cmd.Flags().GetString("alert-name")
 			if err != nil {
@@ -225,6 +315,40 @@ func runSignWithState(cmd *cobra.Command, args []string, state meta.Status) erro
 		return err
 	}
 
+	stdin, err := cmd.Flags().GetBool("stdin")
+	if err != nil {
+		return err
+	}
+	if stdin && name == "" {
+		return fmt.Errorf("please set an asset name, by using --name")
+	}
+
+	atomic, err := cmd.Flags().GetBool("atomic")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	validFromFlag, err := cmd.Flags().GetString("valid-from")
+	if err != nil {
+		return err
+	}
+	validFrom, err := parseValidityFlag("valid-from", validFromFlag, now)
+	if err != nil {
+		return err
+	}
+	validUntilFlag, err := cmd.Flags().GetString("valid-until")
+	if err != nil {
+		return err
+	}
+	validUntil, err := parseValidityFlag("valid-until", validUntilFlag, now)
+	if err != nil {
+		return err
+	}
+	if validFrom != nil && validUntil != nil && !validFrom.Before(*validUntil) {
+		return fmt.Errorf("--valid-from must be before --valid-until")
+	}
+
 	metadata := cmd.Flags().Lookup("attr").Value.(mapOpts).StringToInterface()
 
 	// @todo use dependency injection
@@ -298,12 +422,19 @@ func runSignWithState(cmd *cobra.Command, args []string, state meta.Status) erro
 				artifacts = []*api.Artifact{{Hash: hash}}
 			}
 		} else {
-			artifacts, err = extractor.Extract(args, extractorOptions...)
+			artifacts, err = extractArtifacts(args, stdin, name, extractorOptions...)
 			if err != nil {
 				return err
 			}
 		}
-		return LcSign(lcUser, artifacts, state, output, name, metadata, attachments, lcVerbose)
+		return LcSign(lcUser, artifacts, state, output, name, metadata, attachments, validFrom, validUntil, lcVerbose, atomic)
+	}
+
+	if atomic {
+		return fmt.Errorf("--atomic is supported by CodeNotary Immutable Ledger only")
+	}
+	if validFrom != nil || validUntil != nil {
+		return fmt.Errorf("--valid-from and --valid-until are supported by CodeNotary Immutable Ledger only")
 	}
 
 	// User
@@ -333,7 +464,7 @@ func runSignWithState(cmd *cobra.Command, args []string, state meta.Status) erro
 		}
 	} else {
 		// Extract artifact from arg
-		artifacts, err = extractor.Extract(args, extractorOptions...)
+		artifacts, err = extractArtifacts(args, stdin, name, extractorOptions...)
 		if err != nil {
 			return err
 		}
@@ -461,6 +592,15 @@ func sign(u api.User, a api.Artifact, state meta.Status, visibility meta.Visibil
 	return nil
 }
 
+// extractArtifacts returns the artifacts referenced by args or, if stdin is set,
+// the file artifact named as name for the data streamed on stdin.
+func extractArtifacts(args []string, stdin bool, name string, options ...extractor.Option) ([]*api.Artifact, error) {
+	if stdin {
+		return file.ReaderArtifact(os.Stdin, name, options...)
+	}
+	return extractor.Extract(args, options...)
+}
+
 func pipeMode() bool {
 	fileInfo, _ := os.Stdin.Stat()
 	return fileInfo.Mode()&os.ModeCharDevice == 0
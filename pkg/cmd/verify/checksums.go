@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
+	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/checksums"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/policy"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// checksumsArgs returns true if args reference checksums files only.
+// An error is returned if checksums files are mixed with other kinds of ARG(s).
+func checksumsArgs(args []string) (bool, error) {
+	count := 0
+	for _, arg := range args {
+		if u, err := uri.Parse(arg); err == nil && u.Scheme == checksums.Scheme {
+			count++
+		}
+	}
+	if count > 0 && count != len(args) {
+		return false, fmt.Errorf("cannot use %s:// with other kinds of ARG(s)", checksums.Scheme)
+	}
+	return count > 0, nil
+}
+
+// extractChecksums returns an artifact for each hash listed within the checksums files referenced by args.
+func extractChecksums(args []string, options ...extractor.Option) ([]*api.Artifact, error) {
+	artifacts, err := extractor.Extract(args, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("unable to process the input asset provided: %v", args)
+	}
+	return artifacts, nil
+}
+
+// checkListedFile checks the file listed within a checksums file against the listed hash, if the file is
+// found relative to the checksums file, then removes its path from a. It returns false if the file does not match,
+// along with a note to be reported within the line's status, which also tells whether the file is missing.
+func checkListedFile(a *api.Artifact) (bool, string) {
+	path := file.Metadata(*a)
+	if path == "" {
+		return true, ""
+	}
+	file.RemoveMetadata(a)
+	err := file.CheckDigests(path, map[string]string{file.PrimaryDigestAlgo: a.Hash})
+	switch {
+	case err == nil:
+		return true, ""
+	case os.IsNotExist(err):
+		return true, " (file not found, only the listed hash has been authenticated)"
+	default:
+		return false, fmt.Sprintf(" (%s)", color.New(meta.StyleError()).Sprintf("%s", err))
+	}
+}
+
+// lcVerifyChecksums authenticates each listed hash against the ledger and reports the status per line,
+// the way `sha256sum -c` does, followed by a summary. A listed file not matching its hash is reported as untrusted.
+func lcVerifyChecksums(cmd *cobra.Command, artifacts []*api.Artifact, user *api.LcUser, signers lcSigners, pol *policy.Policy, output string) error {
+	results := make([]*types.LcResult, 0, len(artifacts))
+	for _, a := range artifacts {
+		matched, note := checkListedFile(a)
+		signerID, trusted, err := signers.resolve(user, a.Hash)
+		if err != nil {
+			return err
+		}
+		ar, verified, err := user.LoadArtifact(
+			a.Hash,
+			signerID,
+			"",
+			0,
+			map[string][]string{meta.VcnLCCmdHeaderName: {meta.VcnLCVerifyCmdHeaderValue}})
+		switch err {
+		case nil:
+			if ar.Revoked != nil && !ar.Revoked.IsZero() {
+				ar.Status = meta.StatusApikeyRevoked
+			}
+			expire(ar, time.Now())
+			signers.apply(ar, trusted)
+			if !verified {
+				ar.Status = meta.StatusUnknown
+			}
+		case api.ErrNotFound, api.ErrNotVerified:
+			ar = &api.LcArtifact{
+				Kind:   a.Kind,
+				Name:   a.Name,
+				Hash:   a.Hash,
+				Status: meta.StatusUnknown,
+			}
+		default:
+			return err
+		}
+		if !matched {
+			ar.Status = meta.StatusUntrusted
+		}
+		r := types.NewLcResult(ar, verified, nil)
+		r.SetSigners(trusted, signers.min)
+		// the policy is evaluated against notarized artifacts only
+		if pol != nil && err == nil {
+			if r.Policy, err = lcEvaluatePolicy(user, pol, ar, signerID); err != nil {
+				return err
+			}
+		}
+		if output == "" {
+			fmt.Printf("%s: %s%s%s\n", a.Name, meta.StatusNameStyled(r.Status), note, policyOutcome(r.Policy))
+		}
+		results = append(results, r)
+	}
+
+	if output == "" {
+		printLcSummary(results)
+	} else if err := cli.PrintLcSlice(output, results); err != nil {
+		return err
+	}
+
+	return setLcExitCode(cmd, results)
+}
+
+// verifyChecksums authenticates each listed hash against the blockchain and reports the status per line,
+// the way `sha256sum -c` does, followed by a summary. As for lcVerifyChecksums, no error is returned
+// if any of the listed hashes is not trusted, but the exit code is set accordingly.
+func verifyChecksums(cmd *cobra.Command, artifacts []*api.Artifact, keys []string, pol *policy.Policy, output string) error {
+	results := make([]types.Result, 0, len(artifacts))
+	var failed *api.BlockchainVerification
+	count := 0
+	policyFailed := 0
+	for _, a := range artifacts {
+		matched, note := checkListedFile(a)
+		var verification *api.BlockchainVerification
+		var err error
+		if len(keys) > 0 {
+			verification, err = api.VerifyMatchingSignerIDs(a.Hash, keys)
+		} else {
+			verification, err = api.Verify(a.Hash)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to authenticate the hash: %s", err)
+		}
+		if !matched {
+			verification.Status = meta.StatusUntrusted
+		}
+		r := types.NewResult(a, nil, verification)
+		if pol != nil && !verification.Unknown() {
+			ar, _ := api.LoadArtifact(nil, a.Hash, verification.MetaHash())
+			if r.Policy, err = evaluatePolicy(pol, a.Hash, ar, verification); err != nil {
+				return err
+			}
+			if !r.Policy.Passed {
+				policyFailed++
+			}
+		}
+		if output == "" {
+			fmt.Printf("%s: %s%s%s\n", a.Name, meta.StatusNameStyled(verification.Status), note, policyOutcome(r.Policy))
+		}
+		if !verification.Trusted() {
+			if failed == nil {
+				failed = verification
+			}
+			count++
+		}
+		results = append(results, *r)
+	}
+
+	if output == "" {
+		fmt.Printf("authenticated %d items: %d not trusted", len(artifacts), count)
+		if policyFailed > 0 {
+			fmt.Printf(" (%d failed the policy)", policyFailed)
+		}
+		fmt.Println()
+	} else if err := cli.PrintSlice(output, results); err != nil {
+		return err
+	}
+
+	switch {
+	case failed != nil:
+		viper.Set("exit-code", strconv.Itoa(failed.Status.Int()))
+	case policyFailed > 0:
+		viper.Set("exit-code", strconv.Itoa(meta.VcnPolicyFailedExitCode))
+	default:
+		exitCode, err := cmd.Flags().GetInt("exit-code")
+		if err != nil {
+			return err
+		}
+		viper.Set("exit-code", strconv.Itoa(exitCode))
+	}
+	return nil
+}
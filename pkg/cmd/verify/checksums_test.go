@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/extractor/checksums"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+func TestCheckListedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcn-test-verify-checksums")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// sha256 of "123\n"
+	hash := "181210f8f9c779c26da1d9b2075bde0127302ee0e3fca38c9a83f5b1dd8e5d3b"
+	content := "123\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.txt"), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "SHA256SUMS"), []byte(
+		hash+"  a.txt\n"+hash+"  b.txt\n"+hash+"  c.txt\n",
+	), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := uri.Parse("checksums://" + filepath.Join(dir, "SHA256SUMS"))
+	artifacts, err := checksums.Artifact(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, artifacts, 3)
+
+	// matching
+	matched, note := checkListedFile(artifacts[0])
+	assert.True(t, matched)
+	assert.Empty(t, note)
+	assert.Empty(t, file.Metadata(*artifacts[0]))
+
+	// tampered
+	matched, note = checkListedFile(artifacts[1])
+	assert.False(t, matched)
+	assert.Contains(t, note, "sha256 digest mismatch")
+
+	// missing
+	matched, note = checkListedFile(artifacts[2])
+	assert.True(t, matched)
+	assert.Contains(t, note, "file not found")
+
+	// already checked
+	matched, note = checkListedFile(artifacts[1])
+	assert.True(t, matched)
+	assert.Empty(t, note)
+}
@@ -18,7 +18,11 @@ import (
 	"github.com/vchain-us/vcn/pkg/bundle"
 
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/k8s"
 )
 
 type hook struct {
@@ -33,14 +37,55 @@ func newHook(cmd *cobra.Command, a *api.Artifact) *hook {
 		}
 		h.rawDiff, _ = cmd.Flags().GetBool("raw-diff")
 		dir.RemoveMetadata(a)
+		archive.RemoveMetadata(a)
+		git.RemoveMetadata(a)
+		k8s.RemoveMetadata(a)
+		file.RemoveMetadata(a)
 		return &h
 	}
 	return nil
 }
 
+// manifest returns the manifest and the path of the hooked artifact, if any.
+func (h *hook) manifest() (*bundle.Manifest, string) {
+	if manifest, path := dir.Metadata(h.a); manifest != nil {
+		return manifest, path
+	}
+	if manifest, path := archive.Metadata(h.a); manifest != nil {
+		return manifest, path
+	}
+	if manifest, path := k8s.Metadata(h.a); manifest != nil {
+		return manifest, path
+	}
+	return git.Metadata(h.a)
+}
+
+// checkDigests checks the hooked file against the additional digests recorded within
+// the notarized metadata, if any. Digests are computed by reading the file again, unless
+// they have been already computed while extracting the artifact (e.g. for streamed data).
+func (h *hook) checkDigests(notarized api.Metadata) error {
+	if h == nil || h.a.Kind != file.Scheme {
+		return nil
+	}
+	expected := file.Digests(notarized)
+	if len(expected) == 0 {
+		return nil
+	}
+	var err error
+	if path := file.Metadata(h.a); path != "" {
+		err = file.CheckDigests(path, expected)
+	} else {
+		err = file.MatchDigests(expected, file.Digests(h.a.Metadata))
+	}
+	if err != nil {
+		return fmt.Errorf("%s cannot be authenticated: %s", h.a.Hash, err)
+	}
+	return nil
+}
+
 func (h *hook) finalize(alertConfig *api.AlertConfig, output string) error {
 	if h != nil && output == "" {
-		manifest, path := dir.Metadata(h.a)
+		manifest, path := h.manifest()
 		if manifest != nil && path != "" {
 			oldManifest, err := store.ReadManifest(h.a.Kind, path)
 			if err != nil {
@@ -87,7 +132,7 @@ func (h *hook) finalize(alertConfig *api.AlertConfig, output string) error {
 
 func (h *hook) lcFinalizeWithoutAlert(user *api.LcUser, output string, txId uint64) error {
 	if h != nil && output == "" {
-		manifest, path := dir.Metadata(h.a)
+		manifest, path := h.manifest()
 		if manifest != nil && path != "" {
 			oldManifest, err := store.ReadManifest(h.a.Kind, path)
 			if err != nil {
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/store"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// bundleFile is a file checked against the manifest of a notarized directory (i.e. a bundle).
+type bundleFile struct {
+	path     string // relative to root, OS agnostic
+	root     string
+	manifest *bundle.Manifest
+}
+
+// inBundle checks that the file referenced by arg is listed, with the same content, within the manifest
+// whose digest is manifestHash, and returns the artifact of the directory the manifest belongs to.
+//
+// When manifestPath is empty, the manifest is looked up within the file's directory and its parents,
+// first as bundle.ManifestFilename, then into the local store.
+func inBundle(manifestHash string, manifestPath string, arg string) (*api.Artifact, *bundleFile, error) {
+	u, err := uri.Parse(arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Scheme != "" && u.Scheme != file.Scheme {
+		return nil, nil, fmt.Errorf("--in-bundle can be used with files only, got: %s", arg)
+	}
+	path, err := filepath.Abs(strings.TrimPrefix(u.Opaque, "//"))
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil, fmt.Errorf("read %s: is not a regular file", path)
+	}
+
+	bf, err := findBundle(manifestHash, manifestPath, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	d, err := bundle.NewDescriptor(bf.path, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := bf.check(*d, info); err != nil {
+		return nil, nil, err
+	}
+
+	return &api.Artifact{
+		Kind: dir.Scheme,
+		Name: filepath.Base(bf.root),
+		Hash: manifestHash,
+	}, bf, nil
+}
+
+// findBundle returns the bundleFile for path, using the manifest whose digest is manifestHash.
+func findBundle(manifestHash string, manifestPath string, path string) (*bundleFile, error) {
+	bf := &bundleFile{}
+	if manifestPath != "" {
+		m, err := bundle.ReadManifest(manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := matchManifest(m, manifestHash); err != nil {
+			return nil, fmt.Errorf("%s: %s", manifestPath, err)
+		}
+		root, err := filepath.Abs(filepath.Dir(manifestPath))
+		if err != nil {
+			return nil, err
+		}
+		bf.root, bf.manifest = root, m
+	} else {
+		for root := filepath.Dir(path); bf.manifest == nil; root = filepath.Dir(root) {
+			if m, err := bundle.ReadManifest(filepath.Join(root, bundle.ManifestFilename)); err == nil && matchManifest(m, manifestHash) == nil {
+				bf.root, bf.manifest = root, m
+			} else if m, err := store.ReadManifest(dir.Scheme, root); err == nil && matchManifest(m, manifestHash) == nil {
+				bf.root, bf.manifest = root, m
+			} else if filepath.Dir(root) == root {
+				return nil, fmt.Errorf("no manifest matching %s found for %s", manifestHash, path)
+			}
+		}
+	}
+
+	relPath, err := filepath.Rel(bf.root, path)
+	if err != nil {
+		return nil, err
+	}
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is not within the bundle directory %s", path, bf.root)
+	}
+	// descriptor's path must be OS agnostic
+	bf.path = filepath.ToSlash(relPath)
+	return bf, nil
+}
+
+func matchManifest(m *bundle.Manifest, manifestHash string) error {
+	d, err := m.Digest()
+	if err != nil {
+		return err
+	}
+	if d.Encoded() != manifestHash {
+		return fmt.Errorf("manifest digest does not match %s", manifestHash)
+	}
+	return nil
+}
+
+// check verifies that d (and info, for schema version 2) matches the item listed in the manifest for the bundle's path.
+func (bf *bundleFile) check(d bundle.Descriptor, info os.FileInfo) error {
+	listed := false
+	for _, item := range bf.manifest.Items {
+		for _, p := range item.Paths {
+			if p != bf.path {
+				continue
+			}
+			if item.Digest != d.Digest || item.Size != d.Size {
+				return fmt.Errorf("%s does not match the bundle's content (%s expected, got %s)", bf.path, item.Digest, d.Digest)
+			}
+			listed = true
+		}
+	}
+	if !listed {
+		return fmt.Errorf("%s is not listed within the bundle", bf.path)
+	}
+
+	for _, e := range bf.manifest.Entries {
+		if e.Path == bf.path && e.Mode != bundle.UnixMode(info.Mode()) {
+			return fmt.Errorf("%s does not match the bundle's mode (%04o expected, got %04o)", bf.path, e.Mode, bundle.UnixMode(info.Mode()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/store"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+func TestInBundle(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "vcn-test-in-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	store.SetDir(filepath.Join(tmpDir, store.DefaultDirName))
+
+	root := filepath.Join(tmpDir, "bundle")
+	file := filepath.Join(root, "sub", "file")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(tmpDir, "other")
+	if err := ioutil.WriteFile(other, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := uri.Parse("dir://" + root)
+	artifacts, err := dir.Artifact(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, _ := dir.Metadata(*artifacts[0])
+	hash := artifacts[0].Hash
+	manifestPath := filepath.Join(root, bundle.ManifestFilename)
+	if err := bundle.WriteManifest(*manifest, manifestPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// manifest found within parents
+	a, bf, err := inBundle(hash, "", file)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, a.Hash)
+	assert.Equal(t, dir.Scheme, a.Kind)
+	assert.Equal(t, "sub/file", bf.path)
+	assert.Equal(t, root, bf.root)
+
+	// explicit manifest
+	_, bf, err = inBundle(hash, manifestPath, "file://"+file)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub/file", bf.path)
+
+	// manifest not matching the hash
+	_, _, err = inBundle("0000", "", file)
+	assert.Error(t, err)
+	_, _, err = inBundle("0000", manifestPath, file)
+	assert.Error(t, err)
+
+	// file outside the bundle
+	_, _, err = inBundle(hash, manifestPath, other)
+	assert.Error(t, err)
+
+	// manifest found into the store
+	os.Remove(manifestPath)
+	_, _, err = inBundle(hash, "", file)
+	assert.Error(t, err)
+	assert.NoError(t, store.SaveManifest(dir.Scheme, root, *manifest))
+	_, _, err = inBundle(hash, "", file)
+	assert.NoError(t, err)
+
+	// modified file
+	if err := ioutil.WriteFile(file, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = inBundle(hash, "", file)
+	assert.Error(t, err)
+
+	// unlisted file
+	if err := ioutil.WriteFile(filepath.Join(root, "new"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = inBundle(hash, "", filepath.Join(root, "new"))
+	assert.Error(t, err)
+
+	// not a file
+	_, _, err = inBundle(hash, "", "dir://"+root)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"fmt"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/meta"
+)
+
+// lcSigners holds the SignerIDs an asset is authenticated against on the ledger,
+// and the minimum number of them that must have a trusted notarization of the asset.
+// A zero min means that only the first SignerID (or the current user, if none) is used.
+type lcSigners struct {
+	ids []string
+	min int
+}
+
+// newLcSigners returns the lcSigners for the given ids and min.
+// When more than one SignerID is passed without a min, at least one of them is required.
+func newLcSigners(ids []string, min int) (lcSigners, error) {
+	uniq := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			uniq = append(uniq, id)
+		}
+	}
+
+	switch {
+	case min < 0:
+		return lcSigners{}, fmt.Errorf("--min-signers must be a positive number")
+	case min > 0 && len(uniq) == 0:
+		return lcSigners{}, fmt.Errorf("--min-signers requires the SignerIDs to be passed by --signerID")
+	case min > len(uniq):
+		return lcSigners{}, fmt.Errorf("--min-signers cannot be greater than the number of passed SignerIDs (%d)", len(uniq))
+	case min == 0 && len(uniq) > 1:
+		min = 1
+	}
+	return lcSigners{ids: uniq, min: min}, nil
+}
+
+// primary returns the SignerID used when no threshold is set.
+func (s lcSigners) primary() string {
+	if len(s.ids) > 0 {
+		return s.ids[0]
+	}
+	return ""
+}
+
+// resolve returns the SignerID whose notarization of hash has to be loaded and, if a threshold is set,
+// the SignerIDs with a verified, trusted and not revoked notarization of hash.
+// The first trusted SignerID is preferred, so the loaded notarization is the one that counts.
+func (s lcSigners) resolve(user *api.LcUser, hash string) (signerID string, trusted []string, err error) {
+	if s.min == 0 {
+		return s.primary(), nil, nil
+	}
+	trusted, err = user.TrustedSigners(
+		hash,
+		s.ids,
+		map[string][]string{meta.VcnLCCmdHeaderName: {meta.VcnLCVerifyCmdHeaderValue}})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(trusted) > 0 {
+		return trusted[0], trusted, nil
+	}
+	return s.primary(), trusted, nil
+}
+
+// apply downgrades the status of a trusted ar when fewer than s.min trusted SignerIDs are found.
+func (s lcSigners) apply(ar *api.LcArtifact, trusted []string) {
+	if s.min > 0 && len(trusted) < s.min && ar.Status == meta.StatusTrusted {
+		ar.Status = meta.StatusUntrusted
+	}
+}
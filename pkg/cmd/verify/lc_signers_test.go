@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/meta"
+)
+
+func TestNewLcSigners(t *testing.T) {
+	// no threshold by default, the current user is used
+	s, err := newLcSigners(nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, s.min)
+	assert.Equal(t, "", s.primary())
+
+	s, err = newLcSigners([]string{"alice"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, s.min)
+	assert.Equal(t, "alice", s.primary())
+
+	// any of multiple signers
+	s, err = newLcSigners([]string{"alice", "bob"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.min)
+
+	s, err = newLcSigners([]string{"alice", "bob", "alice", ""}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, lcSigners{ids: []string{"alice", "bob"}, min: 2}, s)
+
+	_, err = newLcSigners([]string{"alice", "alice"}, 2)
+	assert.Error(t, err)
+	_, err = newLcSigners(nil, 1)
+	assert.Error(t, err)
+	_, err = newLcSigners([]string{"alice"}, -1)
+	assert.Error(t, err)
+}
+
+func TestLcSignersApply(t *testing.T) {
+	s := lcSigners{ids: []string{"alice", "bob"}, min: 2}
+
+	ar := &api.LcArtifact{Status: meta.StatusTrusted}
+	s.apply(ar, []string{"alice"})
+	assert.Equal(t, meta.StatusUntrusted, ar.Status)
+
+	ar = &api.LcArtifact{Status: meta.StatusTrusted}
+	s.apply(ar, []string{"alice", "bob"})
+	assert.Equal(t, meta.StatusTrusted, ar.Status)
+
+	// other statuses are retained
+	ar = &api.LcArtifact{Status: meta.StatusApikeyRevoked}
+	s.apply(ar, nil)
+	assert.Equal(t, meta.StatusApikeyRevoked, ar.Status)
+
+	// no threshold
+	ar = &api.LcArtifact{Status: meta.StatusTrusted}
+	lcSigners{ids: []string{"alice"}}.apply(ar, nil)
+	assert.Equal(t, meta.StatusTrusted, ar.Status)
+}
+
+func TestExpire(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	ar := &api.LcArtifact{Status: meta.StatusTrusted, ValidUntil: &past}
+	expire(ar, now)
+	assert.Equal(t, meta.StatusExpired, ar.Status)
+
+	// a threshold of signers does not restore an expired notarization
+	lcSigners{ids: []string{"alice", "bob"}, min: 2}.apply(ar, []string{"alice", "bob"})
+	assert.Equal(t, meta.StatusExpired, ar.Status)
+
+	ar = &api.LcArtifact{Status: meta.StatusTrusted, ValidFrom: &past}
+	expire(ar, now)
+	assert.Equal(t, meta.StatusTrusted, ar.Status)
+
+	// other statuses are retained
+	ar = &api.LcArtifact{Status: meta.StatusUntrusted, ValidUntil: &past}
+	expire(ar, now)
+	assert.Equal(t, meta.StatusUntrusted, ar.Status)
+}
@@ -3,6 +3,7 @@ package verify
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/schollz/progressbar/v3"
@@ -12,20 +13,123 @@ import (
 	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
 	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/policy"
 )
 
-func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signerID string, uid string, attach string, lcAttachForce bool, verbose bool, output string) (err error) {
-	hook := newHook(cmd, a)
-	err = hook.lcFinalizeWithoutAlert(user, output, 0)
+// lcVerifyAll authenticates each of the given artifacts against the ledger and prints the results.
+// When more than one artifact is processed, a summary is printed (or a list of results when output is set)
+// and a single exit code is set for all artifacts.
+func lcVerifyAll(cmd *cobra.Command, artifacts []*api.Artifact, user *api.LcUser, signers lcSigners, uid string, attach string, lcAttachForce bool, verbose bool, pol *policy.Policy, output string) error {
+	results := make([]*types.LcResult, 0, len(artifacts))
+	for _, a := range artifacts {
+		r, err := lcVerify(cmd, a, user, signers, uid, attach, lcAttachForce, verbose, pol, output)
+		if err != nil {
+			return err
+		}
+		results = append(results, r)
+	}
+
+	switch output {
+	case "", "attachments":
+		if len(results) > 1 {
+			printLcSummary(results)
+		}
+	default:
+		var err error
+		if len(results) == 1 {
+			err = cli.PrintLc(output, results[0])
+		} else {
+			err = cli.PrintLcSlice(output, results)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return setLcExitCode(cmd, results)
+}
+
+// setLcExitCode sets the exit code to the status of the first not trusted result, if any.
+// If all results are trusted but any of them fails the policy, meta.VcnPolicyFailedExitCode is used.
+// Otherwise, the user defined exit code is used.
+func setLcExitCode(cmd *cobra.Command, results []*types.LcResult) error {
+	for _, r := range results {
+		if r.Status != meta.StatusTrusted {
+			viper.Set("exit-code", strconv.Itoa(r.Status.Int()))
+			return nil
+		}
+	}
+	for _, r := range results {
+		if r.Policy != nil && !r.Policy.Passed {
+			viper.Set("exit-code", strconv.Itoa(meta.VcnPolicyFailedExitCode))
+			return nil
+		}
+	}
+
+	exitCode, err := cmd.Flags().GetInt("exit-code")
 	if err != nil {
 		return err
 	}
+	viper.Set("exit-code", strconv.Itoa(exitCode))
+	return nil
+}
+
+// expire sets the status of a trusted notarization to meta.StatusExpired, if t is outside its validity window.
+func expire(ar *api.LcArtifact, t time.Time) {
+	if ar.Status == meta.StatusTrusted && !ar.Valid(t) {
+		ar.Status = meta.StatusExpired
+	}
+}
+
+func printLcSummary(results []*types.LcResult) {
+	count := make(map[meta.Status]int)
+	policyFailed := 0
+	for _, r := range results {
+		count[r.Status]++
+		if r.Policy != nil && !r.Policy.Passed {
+			policyFailed++
+		}
+	}
+
+	fmt.Printf("authenticated %d items:", len(results))
+	for _, s := range []meta.Status{
+		meta.StatusTrusted,
+		meta.StatusUntrusted,
+		meta.StatusUnknown,
+		meta.StatusUnsupported,
+		meta.StatusApikeyRevoked,
+		meta.StatusExpired,
+	} {
+		if count[s] > 0 {
+			fmt.Printf(" %d %s", count[s], meta.StatusNameStyled(s))
+		}
+	}
+	if policyFailed > 0 {
+		fmt.Printf(" (%d failed the policy)", policyFailed)
+	}
+	fmt.Println()
+}
+
+// lcVerify authenticates a against the ledger and returns the result.
+// When a minimum number of signers is required, a is trusted only if enough of them have a trusted notarization.
+// The result is printed only when no structured output has been requested.
+func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signers lcSigners, uid string, attach string, lcAttachForce bool, verbose bool, pol *policy.Policy, output string) (*types.LcResult, error) {
+	hook := newHook(cmd, a)
+	err := hook.lcFinalizeWithoutAlert(user, output, 0)
+	if err != nil {
+		return nil, err
+	}
+	signerID, trusted, err := signers.resolve(user, a.Hash)
+	if err != nil {
+		return nil, err
+	}
+
 	var attachmentList []api.Attachment
 
 	if attach != "" {
 		attachmentList, uid, err = user.GetArtifactAttachmentListByLabel(a.Hash, signerID, attach)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -36,23 +140,43 @@ func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signerID st
 		0,
 		map[string][]string{meta.VcnLCCmdHeaderName: {meta.VcnLCVerifyCmdHeaderValue}})
 	if err != nil {
-		if err == api.ErrNotFound {
+		switch err {
+		case api.ErrNotFound:
 			err = fmt.Errorf("%s was not notarized", a.Hash)
-			viper.Set("exit-code", strconv.Itoa(meta.StatusUnknown.Int()))
-		}
-		if err == api.ErrNotVerified {
+		case api.ErrNotVerified:
 			color.Set(meta.StyleError())
 			fmt.Println("the ledger is compromised. Please contact the CodeNotary Immutable Ledger administrators")
 			color.Unset()
 			fmt.Println()
-			viper.Set("exit-code", strconv.Itoa(meta.StatusUnknown.Int()))
+		default:
+			return nil, err
+		}
+		if err := cli.PrintWarning(output, err.Error()); err != nil {
+			return nil, err
 		}
-		return cli.PrintWarning(output, err.Error())
+		// not notarized or not verifiable artifacts are reported as unknown
+		r := types.NewLcResult(&api.LcArtifact{
+			Kind:   a.Kind,
+			Name:   a.Name,
+			Hash:   a.Hash,
+			Size:   a.Size,
+			Status: meta.StatusUnknown,
+		}, false, nil)
+		r.SetSigners(trusted, signers.min)
+		return r, nil
 	}
 	if ar.Revoked != nil && !ar.Revoked.IsZero() {
-		viper.Set("exit-code", strconv.Itoa(meta.StatusApikeyRevoked.Int()))
 		ar.Status = meta.StatusApikeyRevoked
 	}
+	expire(ar, time.Now())
+	signers.apply(ar, trusted)
+	// a digest mismatch only affects this artifact, so the remaining ones are still authenticated
+	if err := hook.checkDigests(ar.Metadata); err != nil {
+		if err := cli.PrintWarning(output, err.Error()); err != nil {
+			return nil, err
+		}
+		ar.Status = meta.StatusUntrusted
+	}
 
 	if len(attachmentList) == 0 && ar.Attachments != nil {
 		attachmentList = ar.Attachments
@@ -71,7 +195,7 @@ func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signerID st
 			_ = bar.Add(1)
 			err := user.DownloadAttachment(&a, ar, 0, lcAttachForce)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 		fmt.Println()
@@ -81,20 +205,9 @@ func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signerID st
 		fmt.Println("the ledger is compromised. Please contact the CodeNotary Immutable Ledger administrators")
 		color.Unset()
 		fmt.Println()
-		viper.Set("exit-code", strconv.Itoa(meta.StatusUnknown.Int()))
 		ar.Status = meta.StatusUnknown
 	}
 
-	exitCode, err := cmd.Flags().GetInt("exit-code")
-	if err != nil {
-		return err
-	}
-	// if exitCode == VcnDefaultExitCode user didn't specify to use a custom exit code in case of success.
-	// In that case we return the ar.Status as exit code.
-	// User defined exit code is returned only if the viper exit-code status is == 0 (status trusted)
-	if exitCode == meta.VcnDefaultExitCode && viper.GetInt("exit-code") == 0 {
-		viper.Set("exit-code", strconv.Itoa(ar.Status.Int()))
-	}
 	var verbInfos *types.LcVerboseInfo
 	if verbose {
 		verbInfos = &types.LcVerboseInfo{
@@ -103,7 +216,18 @@ func lcVerify(cmd *cobra.Command, a *api.Artifact, user *api.LcUser, signerID st
 			ApiKey:     user.Client.ApiKey,
 		}
 	}
-	cli.PrintLc(output, types.NewLcResult(ar, verified, verbInfos))
+	r := types.NewLcResult(ar, verified, verbInfos)
+	r.SetSigners(trusted, signers.min)
+	if pol != nil {
+		if r.Policy, err = lcEvaluatePolicy(user, pol, ar, signerID); err != nil {
+			return nil, err
+		}
+	}
+	if output == "" || output == "attachments" {
+		if err := cli.PrintLc(output, r); err != nil {
+			return nil, err
+		}
+	}
 
-	return
+	return r, nil
 }
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/policy"
+)
+
+// loadPolicy returns the policy referenced by the --policy flag, or nil if not set.
+func loadPolicy(cmd *cobra.Command) (*policy.Policy, error) {
+	path, err := cmd.Flags().GetString("policy")
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return policy.Load(path)
+}
+
+// lcEvaluatePolicy evaluates pol against ar, the notarization loaded from the ledger for signerID.
+// The listed signers and the attachment labels required by pol are looked up on the ledger.
+func lcEvaluatePolicy(user *api.LcUser, pol *policy.Policy, ar *api.LcArtifact, signerID string) (*policy.Report, error) {
+	s := policy.Subject{
+		Timestamp: ar.Timestamp,
+		Metadata:  ar.Metadata,
+	}
+
+	if pol.Signers != nil {
+		signers, err := user.TrustedSigners(
+			ar.Hash,
+			pol.Signers.IDs,
+			map[string][]string{meta.VcnLCCmdHeaderName: {meta.VcnLCVerifyCmdHeaderValue}})
+		if err != nil {
+			return nil, err
+		}
+		s.Signers = signers
+	}
+
+	for _, label := range pol.Attachments {
+		attachments, _, err := user.GetArtifactAttachmentListByLabel(ar.Hash, signerID, label)
+		if err == api.ErrAttachmentLabelNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(attachments) > 0 {
+			s.Attachments = append(s.Attachments, label)
+		}
+	}
+
+	return pol.Evaluate(s, time.Now()), nil
+}
+
+// evaluatePolicy evaluates pol against the blockchain verification of hash and its artifact ar, if any.
+// Attachments are not supported by the blockchain, so any attachment rule fails.
+func evaluatePolicy(pol *policy.Policy, hash string, ar *api.ArtifactResponse, verification *api.BlockchainVerification) (*policy.Report, error) {
+	s := policy.Subject{
+		Timestamp: verification.Timestamp,
+	}
+	if ar != nil {
+		s.Metadata = ar.Metadata
+	}
+
+	if pol.Signers != nil {
+		for _, id := range pol.Signers.IDs {
+			v, err := api.VerifyMatchingSignerIDs(hash, []string{id})
+			if err != nil {
+				return nil, err
+			}
+			if v.Trusted() {
+				s.Signers = append(s.Signers, id)
+			}
+		}
+	}
+
+	return pol.Evaluate(s, time.Now()), nil
+}
+
+// policyOutcome returns a short, single line description of r, or an empty string if r is nil.
+func policyOutcome(r *policy.Report) string {
+	switch {
+	case r == nil:
+		return ""
+	case r.Passed:
+		return ""
+	}
+	violations := make([]string, len(r.Violations))
+	for i, v := range r.Violations {
+		violations[i] = v.String()
+	}
+	return fmt.Sprintf(" (%s: %s)", color.New(meta.StyleError()).Sprintf("policy FAILED"), strings.Join(violations, "; "))
+}
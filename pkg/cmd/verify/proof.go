@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
+	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
+	"github.com/vchain-us/vcn/pkg/meta"
+)
+
+// verifyProof authenticates a against the proof bundle at path, as exported by `vcn export-proof`.
+// The proofs are verified locally, so no network access is needed. The ledger state is authenticated only
+// if the public signing key of the ledger server is given by pubKeyPath, otherwise a is reported as UNKNOWN
+// even if the proofs are consistent. If any signerIDs are given, the bundle must refer to one of them.
+func verifyProof(cmd *cobra.Command, a *api.Artifact, path string, pubKeyPath string, signerIDs []string, output string) error {
+	b, err := api.LoadProofBundle(path)
+	if err != nil {
+		return err
+	}
+	if len(signerIDs) > 0 && !contains(signerIDs, b.SignerID()) {
+		return fmt.Errorf("the proof bundle refers to the notarization by %s, not by %s", b.SignerID(), strings.Join(signerIDs, ", "))
+	}
+	var pubKey *ecdsa.PublicKey
+	if pubKeyPath != "" {
+		if pubKey, err = api.LoadSigningPubKey(pubKeyPath); err != nil {
+			return err
+		}
+	}
+
+	// the diff against the previously notarized manifest is not available, since it needs the ledger
+	hook := newHook(cmd, a)
+
+	verified := true
+	ar, state, err := b.Verify(a.Hash, pubKey)
+	switch err {
+	case nil:
+		if pubKey == nil {
+			if output == "" {
+				color.Set(meta.StyleWarning())
+				fmt.Println("the proofs are consistent, but the ledger state is not authenticated: use --lc-signing-pub-key to authenticate it")
+				color.Unset()
+				fmt.Println()
+			}
+			verified = false
+			ar.Status = meta.StatusUnknown
+		} else if ar.Revoked != nil && !ar.Revoked.IsZero() {
+			ar.Status = meta.StatusApikeyRevoked
+		}
+		expire(ar, time.Now())
+		if err := hook.checkDigests(ar.Metadata); err != nil {
+			if err := cli.PrintWarning(output, err.Error()); err != nil {
+				return err
+			}
+			ar.Status = meta.StatusUntrusted
+		}
+	case api.ErrNotVerified:
+		if output == "" {
+			color.Set(meta.StyleError())
+			fmt.Println("the proof bundle is not valid, its proofs or the ledger state signature do not verify")
+			color.Unset()
+			fmt.Println()
+		}
+		verified = false
+		ar = &api.LcArtifact{
+			Kind:   a.Kind,
+			Name:   a.Name,
+			Hash:   a.Hash,
+			Size:   a.Size,
+			Status: meta.StatusUnknown,
+		}
+	default:
+		return err
+	}
+
+	r := types.NewLcResult(ar, verified, nil)
+	if err := cli.PrintLc(output, r); err != nil {
+		return err
+	}
+	if output == "" && verified {
+		fmt.Printf("\nVerified offline against the signed ledger state at transaction %d (%s)\n", state.TxId, hex.EncodeToString(state.TxHash))
+	}
+
+	return setLcExitCode(cmd, []*types.LcResult{r})
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
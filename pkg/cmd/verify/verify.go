@@ -10,6 +10,7 @@ package verify
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -19,10 +20,16 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/cli"
 	"github.com/vchain-us/vcn/pkg/cmd/internal/types"
 	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/extractor/git"
+	"github.com/vchain-us/vcn/pkg/extractor/wildcard"
 	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/policy"
 	"github.com/vchain-us/vcn/pkg/store"
 )
 
@@ -42,7 +49,7 @@ func getSignerIDs() []string {
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "authenticate",
-		Example: "  vcn authenticate /bin/vcn",
+		Example: "  vcn authenticate /bin/vcn\n  cat /bin/vcn | vcn authenticate --stdin",
 		Aliases: []string{"a", "verify", "v"},
 		Short:   "Authenticate assets against the blockchain",
 		Long: `
@@ -67,9 +74,51 @@ ARG must be one of:
   <file>
   file://<file>
   dir://<directory>
-  git://<repository>
+  archive://<file>
+  git://<repository>[@<ref>]
+  git://<repository>#range=<from>..<to>
   docker://<image>
   podman://<image>
+  oci://<directory>[:<ref>]
+  docker-archive://<file>[:<repo:tag>]
+  checksums://<file>
+  k8s://<file-or-directory>
+
+With checksums://, each hash listed within a sha256sum (GNU or BSD format)
+file is authenticated and the status is reported per line, the way
+'sha256sum -c' does. The listed files are not needed, but when a listed file
+is found relative to the checksums file, its content is hashed too and the
+line is reported as UNTRUSTED if it does not match the listed hash.
+
+With --in-bundle, a single file is authenticated against the manifest of a
+notarized directory: the file must be listed within the manifest with the same
+content, and the manifest's hash must be authenticated. The manifest is read
+from the '.vcn.manifest.json' file (or the local store) of the file's
+directory or any of its parents, unless --bundle-manifest is given.
+
+On CodeNotary Immutable Ledger, when multiple SignerIDs are passed, at least
+one of them (or the number given by --min-signers) must have a trusted and not
+revoked notarization of the asset, otherwise the asset is reported as UNTRUSTED.
+
+With --policy, the authenticated assets must also satisfy the rules of the
+given policy file: a minimum number of trusted signers out of a list, a maximum
+age of the notarization, required metadata values and attachment labels.
+Failed rules are reported, and the exit code will be 6 if any is found.
+
+On CodeNotary Immutable Ledger, an asset notarized with a validity window
+(by --valid-from and --valid-until) is reported as EXPIRED outside of it, and
+the exit code will be 5.
+
+With --proof, a single asset is authenticated offline against a proof bundle
+exported by 'vcn export-proof': the inclusion of the notarization within the
+ledger and the consistency with the bundled ledger state are verified locally.
+The ledger state is authenticated by the server's signature, so the public
+signing key of the ledger server must be given by --lc-signing-pub-key,
+otherwise the asset is reported as UNKNOWN. The bundle must refer to the
+notarization by the given SignerID (or by the current user's one, if known).
+
+With --stdin, the data streamed on stdin is authenticated as a file, without
+storing it on disk.
 
 Environment variables:
 VCN_USER=
@@ -100,6 +149,22 @@ VCN_LC_LEDGER=
 			}
 
 			alerts, _ := cmd.Flags().GetBool("alerts")
+			if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+				inBundle, _ := cmd.Flags().GetString("in-bundle")
+				if hash, _ := cmd.Flags().GetString("hash"); hash != "" || inBundle != "" || alerts {
+					return fmt.Errorf("cannot use --stdin with --hash, --in-bundle or --alerts")
+				}
+				if len(args) > 0 {
+					return fmt.Errorf("cannot use ARG(s) with --stdin")
+				}
+				return nil
+			}
+			if inBundle, _ := cmd.Flags().GetString("in-bundle"); inBundle != "" {
+				if hash, _ := cmd.Flags().GetString("hash"); hash != "" || alerts {
+					return fmt.Errorf("cannot use --in-bundle with --hash or --alerts")
+				}
+				return cobra.ExactArgs(1)(cmd, args)
+			}
 			if alerts {
 				if len(args) > 0 {
 					return fmt.Errorf("cannot use ARG(s) with --alerts")
@@ -126,12 +191,22 @@ VCN_LC_LEDGER=
 	)
 
 	cmd.Flags().StringSliceP("signerID", "s", nil, "accept only authentications matching the passed SignerID(s)\n(overrides VCN_SIGNERID env var, if any). It's valid both for blockchain and ledger compliance")
+	cmd.Flags().Int("min-signers", 0, "minimum number of the passed SignerIDs that must have a trusted notarization of the asset\n(CodeNotary Immutable Ledger only, defaults to 1 when multiple SignerIDs are passed)")
 	cmd.Flags().StringSliceP("key", "k", nil, "")
 	cmd.Flags().MarkDeprecated("key", "please use --signerID instead")
 	cmd.Flags().StringP("org", "I", "", "accept only authentications matching the passed organisation's ID,\nif set no SignerID can be used\n(overrides VCN_ORG env var, if any)")
 	cmd.Flags().String("hash", "", "specify a hash to authenticate, if set no ARG(s) can be used")
+	cmd.Flags().Bool("stdin", false, "if set, the data streamed on stdin will be authenticated as a file, no ARG(s) can be used")
+	cmd.Flags().String("in-bundle", "", "specify the hash of a notarized directory's manifest to authenticate a single file against it, exactly one file ARG must be used")
+	cmd.Flags().String("bundle-manifest", "", "specify the manifest file to be used with --in-bundle (the file's path is relative to the manifest's directory)")
 	cmd.Flags().Bool("alerts", false, "specify to authenticate and monitor for the configured alerts, if set no ARG(s) can be used")
 	cmd.Flags().Bool("raw-diff", false, "print raw a diff, if any")
+	cmd.Flags().Bool("archive", false, "if set, tar, tar.gz and zip files passed as ARG(s) will be processed by their content (as archive://)")
+	cmd.Flags().Bool("git-tree", false, "if set, git commits will be hashed by their tree content, matching dir:// of the same checkout only if a committed .vcnignore excludes .git (affects git:// only)")
+	cmd.Flags().Bool("gitignore", false, "if set, .gitignore files will be honoured as well as .vcnignore ones (affects dir:// only)")
+	cmd.Flags().Int("jobs", 0, "number of files to hash concurrently, defaults to the number of CPUs (affects dir:// and wildcard usage only)")
+	cmd.Flags().Bool("no-cache", false, "if set, the local hash cache is not used even if enabled by 'vcn cache enable' (affects dir:// only)")
+	cmd.Flags().Uint("manifest-version", bundle.ManifestSchemaVersion, "manifest schema version, version 2 records file modes, symlinks and empty directories too (affects dir:// only)")
 	cmd.Flags().Int("exit-code", meta.VcnDefaultExitCode, meta.VcnExitCode)
 	cmd.Flags().String("lc-host", "", meta.VcnLcHostFlagDesc)
 	cmd.Flags().String("lc-port", "443", meta.VcnLcPortFlagDesc)
@@ -142,6 +217,9 @@ VCN_LC_LEDGER=
 	cmd.Flags().String("lc-ledger", "", meta.VcnLcLedgerDesc)
 	cmd.Flags().String("lc-uid", "", meta.VcnLcUidDesc)
 	cmd.Flags().String("attach", "", meta.VcnLcAttachmentAuthDesc)
+	cmd.Flags().String("lc-signing-pub-key", "", "path to the PEM encoded public signing key of the CodeNotary Immutable Ledger server, used to authenticate the ledger state of a proof bundle (affects --proof only)")
+	cmd.Flags().String("proof", "", "specify a proof bundle exported by 'vcn export-proof' to authenticate a single asset offline, without any network access")
+	cmd.Flags().String("policy", "", "specify a policy file (YAML or JSON) that authenticated assets must satisfy, failed rules are reported and the exit code will be 6")
 	cmd.Flags().Bool("force", false, meta.VcnLcForceAttachmentDownloadDesc)
 
 	cmd.Flags().MarkHidden("raw-diff")
@@ -166,8 +244,140 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// default extractors options
+	extractorOptions := []extractor.Option{}
+
+	archive, err := cmd.Flags().GetBool("archive")
+	if err != nil {
+		return err
+	}
+	if archive {
+		extractorOptions = append(extractorOptions, wildcard.WithArchive())
+	}
+
+	gitTree, err := cmd.Flags().GetBool("git-tree")
+	if err != nil {
+		return err
+	}
+	if gitTree {
+		extractorOptions = append(extractorOptions, git.WithTreeContent())
+	}
+
+	gitIgnore, err := cmd.Flags().GetBool("gitignore")
+	if err != nil {
+		return err
+	}
+	if gitIgnore {
+		extractorOptions = append(extractorOptions, dir.WithGitIgnore(), wildcard.WithGitIgnore())
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithJobs(jobs), wildcard.WithJobs(jobs))
+
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		return err
+	}
+	if store.HashCacheEnabled() && !noCache {
+		hashCache := store.LoadHashCache()
+		// cache is optional, so errors are ignored
+		defer hashCache.Save()
+		extractorOptions = append(extractorOptions, dir.WithHashCache(hashCache), wildcard.WithHashCache(hashCache))
+	}
+
+	manifestVersion, err := cmd.Flags().GetUint("manifest-version")
+	if err != nil {
+		return err
+	}
+	extractorOptions = append(extractorOptions, dir.WithManifestVersion(manifestVersion), wildcard.WithManifestVersion(manifestVersion))
+
 	cmd.SilenceUsage = true
 
+	pol, err := loadPolicy(cmd)
+	if err != nil {
+		return err
+	}
+
+	inBundleHash, err := cmd.Flags().GetString("in-bundle")
+	if err != nil {
+		return err
+	}
+	bundleManifest, err := cmd.Flags().GetString("bundle-manifest")
+	if err != nil {
+		return err
+	}
+	var bundleArtifact *api.Artifact
+	if inBundleHash != "" {
+		a, bf, err := inBundle(strings.ToLower(inBundleHash), bundleManifest, args[0])
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			fmt.Printf("%s is listed within the bundle %s (%s)\n", bf.path, a.Hash, bf.root)
+		}
+		bundleArtifact = a
+	}
+
+	stdin, err := cmd.Flags().GetBool("stdin")
+	if err != nil {
+		return err
+	}
+	var stdinArtifact *api.Artifact
+	if stdin {
+		// all supported digests are computed, since the stream cannot be read again
+		// for checking the ones recorded by the notarization
+		artifacts, err := file.ReaderArtifact(os.Stdin, "", append(extractorOptions, file.WithDigests(file.DigestAlgos()...))...)
+		if err != nil {
+			return err
+		}
+		stdinArtifact = artifacts[0]
+	}
+
+	byChecksums, err := checksumsArgs(args)
+	if err != nil {
+		return err
+	}
+
+	proofPath, err := cmd.Flags().GetString("proof")
+	if err != nil {
+		return err
+	}
+	if proofPath != "" {
+		if useAlerts || byChecksums || pol != nil {
+			return fmt.Errorf("cannot use --proof with --alerts, --policy or checksums://")
+		}
+		if minSigners, _ := cmd.Flags().GetInt("min-signers"); minSigners != 0 {
+			return fmt.Errorf("cannot use --proof with --min-signers")
+		}
+		var a *api.Artifact
+		switch {
+		case bundleArtifact != nil:
+			a = bundleArtifact
+		case stdinArtifact != nil:
+			a = stdinArtifact
+		case hash != "":
+			a = &api.Artifact{Hash: strings.ToLower(hash)}
+		default:
+			artifacts, err := extractor.Extract(args, extractorOptions...)
+			if err != nil {
+				return err
+			}
+			if len(artifacts) != 1 {
+				return fmt.Errorf("a proof bundle can authenticate exactly one asset, %d found", len(artifacts))
+			}
+			a = artifacts[0]
+		}
+		signerIDs := getSignerIDs()
+		// by default, the bundle must refer to the current user's notarization, if any
+		if lcApiKey := viper.GetString("lc-api-key"); len(signerIDs) == 0 && lcApiKey != "" {
+			signerIDs = []string{api.GetSignerIDByApiKey(lcApiKey)}
+		}
+		return verifyProof(cmd, a, proofPath, viper.GetString("lc-signing-pub-key"), signerIDs, output)
+	}
+
 	lcHost := viper.GetString("lc-host")
 	lcPort := viper.GetString("lc-port")
 	lcCert := viper.GetString("lc-cert")
@@ -202,37 +412,65 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	minSigners, err := cmd.Flags().GetInt("min-signers")
+	if err != nil {
+		return err
+	}
+
 	if lcUser != nil {
-		var signerID string
-		signerIDs := getSignerIDs()
-		if len(signerIDs) > 0 {
-			signerID = signerIDs[0]
+		signers, err := newLcSigners(getSignerIDs(), minSigners)
+		if err != nil {
+			return err
 		}
 		err = lcUser.Client.Connect()
 		if err != nil {
 			return err
 		}
+		// by bundle
+		if bundleArtifact != nil {
+			return lcVerifyAll(cmd, []*api.Artifact{bundleArtifact}, lcUser, signers, lcUid, lcAttach, lcAttachForce, lcVerbose, pol, output)
+		}
+
+		// by stdin
+		if stdinArtifact != nil {
+			return lcVerifyAll(cmd, []*api.Artifact{stdinArtifact}, lcUser, signers, lcUid, lcAttach, lcAttachForce, lcVerbose, pol, output)
+		}
+
 		// by hash
 		if hash != "" {
 			a := &api.Artifact{
 				Hash: strings.ToLower(hash),
 			}
-			return lcVerify(cmd, a, lcUser, signerID, lcUid, lcAttach, lcAttachForce, lcVerbose, output)
+			return lcVerifyAll(cmd, []*api.Artifact{a}, lcUser, signers, lcUid, lcAttach, lcAttachForce, lcVerbose, pol, output)
 		}
 
-		artifacts, err := extractor.Extract([]string{args[0]})
-		if err != nil {
-			return err
+		// by checksums files
+		if byChecksums {
+			artifacts, err := extractChecksums(args, extractorOptions...)
+			if err != nil {
+				return err
+			}
+			return lcVerifyChecksums(cmd, artifacts, lcUser, signers, pol, output)
 		}
-		for _, a := range artifacts {
-			err := lcVerify(cmd, a, lcUser, signerID, lcUid, lcAttach, lcAttachForce, lcVerbose, output)
+
+		// by args
+		artifacts := make([]*api.Artifact, 0, len(args))
+		for _, arg := range args {
+			ars, err := extractor.Extract([]string{arg}, extractorOptions...)
 			if err != nil {
 				return err
 			}
+			if ars == nil {
+				return fmt.Errorf("unable to process the input asset provided: %s", arg)
+			}
+			artifacts = append(artifacts, ars...)
 		}
-		return nil
+		return lcVerifyAll(cmd, artifacts, lcUser, signers, lcUid, lcAttach, lcAttachForce, lcVerbose, pol, output)
 	}
 
+	if minSigners != 0 {
+		return fmt.Errorf("--min-signers is supported by CodeNotary Immutable Ledger only")
+	}
 	if output == "attachments" {
 		return fmt.Errorf("in order to download attachments, you need to be logged in on CodeNotary Immutable Ledger®\nProceed by authenticating yourself using <vcn login>")
 	}
@@ -297,7 +535,7 @@ func runVerify(cmd *cobra.Command, args []string) error {
 			}
 			alertConfig.Metadata["arg"] = alert.Arg
 
-			artifacts, err := extractor.Extract([]string{alert.Arg})
+			artifacts, err := extractor.Extract([]string{alert.Arg}, extractorOptions...)
 			if err != nil {
 				cli.PrintWarning(output, err.Error())
 				alertConfig.Metadata["error"] = err.Error()
@@ -311,7 +549,7 @@ func runVerify(cmd *cobra.Command, args []string) error {
 				continue
 			}
 			for _, a := range artifacts {
-				if err := verify(cmd, a, keys, org, user, &alertConfig, output); err != nil {
+				if err := verify(cmd, a, keys, org, user, &alertConfig, pol, output); err != nil {
 					cli.PrintWarning(output, fmt.Sprintf("%s: %s", alert.Arg, err))
 				}
 				if output == "" {
@@ -322,20 +560,39 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// by bundle
+	if bundleArtifact != nil {
+		return verify(cmd, bundleArtifact, keys, org, user, nil, pol, output)
+	}
+
+	// by stdin
+	if stdinArtifact != nil {
+		return verify(cmd, stdinArtifact, keys, org, user, nil, pol, output)
+	}
+
 	// by hash
 	if hash != "" {
 		a := &api.Artifact{
 			Hash: strings.ToLower(hash),
 		}
-		if err := verify(cmd, a, keys, org, user, nil, output); err != nil {
+		if err := verify(cmd, a, keys, org, user, nil, pol, output); err != nil {
 			return err
 		}
 		return nil
 	}
 
+	// by checksums files
+	if byChecksums {
+		artifacts, err := extractChecksums(args, extractorOptions...)
+		if err != nil {
+			return err
+		}
+		return verifyChecksums(cmd, artifacts, keys, pol, output)
+	}
+
 	// by args
 	for _, arg := range args {
-		artifacts, err := extractor.Extract([]string{arg})
+		artifacts, err := extractor.Extract([]string{arg}, extractorOptions...)
 		if err != nil {
 			return err
 		}
@@ -343,7 +600,7 @@ func runVerify(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("unable to process the input asset provided: %s", arg)
 		}
 		for _, a := range artifacts {
-			if err := verify(cmd, a, keys, org, user, nil, output); err != nil {
+			if err := verify(cmd, a, keys, org, user, nil, pol, output); err != nil {
 				return err
 			}
 		}
@@ -352,7 +609,7 @@ func runVerify(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func verify(cmd *cobra.Command, a *api.Artifact, keys []string, org string, user *api.User, alertConfig *api.AlertConfig, output string) (err error) {
+func verify(cmd *cobra.Command, a *api.Artifact, keys []string, org string, user *api.User, alertConfig *api.AlertConfig, pol *policy.Policy, output string) (err error) {
 	hook := newHook(cmd, a)
 	var verification *api.BlockchainVerification
 	if output == "" {
@@ -419,8 +676,19 @@ func verify(cmd *cobra.Command, a *api.Artifact, keys []string, org string, user
 	if !verification.Unknown() {
 		ar, _ = api.LoadArtifact(user, a.Hash, verification.MetaHash())
 	}
+	mismatch, err := untrustOnDigestMismatch(hook, ar, verification, output)
+	if err != nil {
+		return err
+	}
+
+	r := types.NewResult(a, ar, verification)
+	if pol != nil && !verification.Unknown() {
+		if r.Policy, err = evaluatePolicy(pol, a.Hash, ar, verification); err != nil {
+			return err
+		}
+	}
 
-	if err = cli.Print(output, types.NewResult(a, ar, verification)); err != nil {
+	if err = cli.Print(output, r); err != nil {
 		return err
 	}
 
@@ -452,6 +720,12 @@ func verify(cmd *cobra.Command, a *api.Artifact, keys []string, org string, user
 		api.TrackPublisher(user, meta.VcnVerifyEvent)
 	}
 
+	// a digest mismatch has been already reported, so the remaining assets are still authenticated
+	if mismatch {
+		viper.Set("exit-code", strconv.Itoa(verification.Status.Int()))
+		return nil
+	}
+
 	if !verification.Trusted() {
 		errLabels := map[meta.Status]string{
 			meta.StatusUnknown:     "was not notarized",
@@ -473,5 +747,26 @@ func verify(cmd *cobra.Command, a *api.Artifact, keys []string, org string, user
 		}
 	}
 
+	if r.Policy != nil && !r.Policy.Passed {
+		viper.Set("exit-code", strconv.Itoa(meta.VcnPolicyFailedExitCode))
+		return fmt.Errorf("%s does not satisfy the policy", a.Hash)
+	}
+
 	return
 }
+
+// untrustOnDigestMismatch marks verification as untrusted if the hooked file does not match
+// the additional digests recorded within the notarized metadata, and reports the mismatch as a warning.
+func untrustOnDigestMismatch(hook *hook, ar *api.ArtifactResponse, verification *api.BlockchainVerification, output string) (bool, error) {
+	if ar == nil || verification == nil {
+		return false, nil
+	}
+	if err := hook.checkDigests(ar.Metadata); err != nil {
+		if err := cli.PrintWarning(output, err.Error()); err != nil {
+			return false, err
+		}
+		verification.Status = meta.StatusUntrusted
+		return true, nil
+	}
+	return false, nil
+}
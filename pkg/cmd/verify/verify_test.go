@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package verify
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/meta"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+func TestUntrustOnDigestMismatch(t *testing.T) {
+	f, err := ioutil.TempFile("", "vcn-test-verify-digests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if err := ioutil.WriteFile(f.Name(), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := uri.Parse("file://" + f.Name())
+	artifacts, err := file.Artifact(u, file.WithDigests("sha512"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := artifacts[0]
+	hook := newHook(&cobra.Command{}, a)
+	ar := &api.ArtifactResponse{Metadata: a.Metadata}
+
+	// recorded digests match
+	verification := &api.BlockchainVerification{Status: meta.StatusTrusted}
+	mismatch, err := untrustOnDigestMismatch(hook, ar, verification, "")
+	assert.NoError(t, err)
+	assert.False(t, mismatch)
+	assert.Equal(t, meta.StatusTrusted, verification.Status)
+
+	// not notarized
+	mismatch, err = untrustOnDigestMismatch(hook, nil, verification, "")
+	assert.NoError(t, err)
+	assert.False(t, mismatch)
+
+	// content changed
+	if err := ioutil.WriteFile(f.Name(), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mismatch, err = untrustOnDigestMismatch(hook, ar, verification, "")
+	assert.NoError(t, err)
+	assert.True(t, mismatch)
+	assert.Equal(t, meta.StatusUntrusted, verification.Status)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// Scheme for archive
+const Scheme = "archive"
+
+// ManifestKey is the metadata's key for storing the manifest
+const ManifestKey = "manifest"
+
+// PathKey is the metadata's key for the archive path
+const PathKey = "path"
+
+// FormatKey is the metadata's key for the archive format
+const FormatKey = "format"
+
+// Artifact returns an archive *api.Artifact from a given u.
+//
+// The archive's entries are read as a stream and the resulting artifact's hash is the digest of
+// the bundle.Manifest built from them, so the same content always results in the same hash
+// regardless of how the archive has been packed or compressed.
+func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
+
+	if u.Scheme != Scheme {
+		return nil, nil
+	}
+
+	path := strings.TrimPrefix(u.Opaque, "//")
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if stat.IsDir() {
+		return nil, fmt.Errorf("read %s: is a directory", path)
+	}
+
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		return nil, fmt.Errorf("read %s: unsupported archive format", path)
+	}
+
+	files, err := entries(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := bundle.NewManifest(files...)
+	digest, err := manifest.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	// Metadata container
+	m := api.Metadata{
+		ManifestKey: manifest,
+		PathKey:     path,
+		FormatKey:   format,
+	}
+
+	return []*api.Artifact{{
+		Kind:        Scheme,
+		Hash:        digest.Encoded(),
+		Name:        stat.Name(),
+		Size:        uint64(stat.Size()),
+		ContentType: contentTypes[format],
+		Metadata:    m,
+	}}, nil
+}
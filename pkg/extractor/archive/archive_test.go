@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+var testEntries = []struct {
+	name    string
+	content string
+}{
+	{"./a.txt", "aaa"},
+	{"b/b.txt", "bbb"},
+	{"b/c.txt", "aaa"},
+}
+
+func writeTar(t *testing.T, w io.Writer) {
+	tw := tar.NewWriter(w)
+	for _, e := range testEntries {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     e.name,
+			Mode:     0644,
+			Size:     int64(len(e.content)),
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}))
+		_, err := tw.Write([]byte(e.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+}
+
+func writeZip(t *testing.T, w io.Writer) {
+	zw := zip.NewWriter(w)
+	for _, e := range testEntries {
+		fw, err := zw.Create(e.name)
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(e.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+}
+
+func TestArtifact(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TempDir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	create := func(name string, write func(io.Writer)) string {
+		filename := filepath.Join(tmpDir, name)
+		f, err := os.Create(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		write(f)
+		return filename
+	}
+
+	tarFile := create("test.tar", func(w io.Writer) { writeTar(t, w) })
+	tgzFile := create("test.tar.gz", func(w io.Writer) {
+		zw := gzip.NewWriter(w)
+		writeTar(t, zw)
+		assert.NoError(t, zw.Close())
+	})
+	zipFile := create("test.zip", func(w io.Writer) { writeZip(t, w) })
+	txtFile := create("test.txt", func(w io.Writer) { w.Write([]byte("not an archive")) })
+
+	var hash string
+	for format, filename := range map[string]string{
+		FormatTar:   tarFile,
+		FormatTarGz: tgzFile,
+		FormatZip:   zipFile,
+	} {
+		u, _ := uri.Parse("archive://" + filename)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 1)
+		assert.Equal(t, Scheme, artifacts[0].Kind)
+		assert.Equal(t, filepath.Base(filename), artifacts[0].Name)
+		assert.Equal(t, format, artifacts[0].Metadata[FormatKey])
+
+		manifest, path := Metadata(*artifacts[0])
+		assert.Equal(t, filename, path)
+		assert.Len(t, manifest.Items, 2)
+
+		// same content, same hash
+		if hash == "" {
+			hash = artifacts[0].Hash
+		}
+		assert.Equal(t, hash, artifacts[0].Hash)
+	}
+
+	// wrong schema - SKIP (no error)
+	u, _ := uri.Parse("file://" + tarFile)
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Nil(t, artifacts)
+
+	// not an archive - ERROR
+	u, _ = uri.Parse("archive://" + txtFile)
+	artifacts, err = Artifact(u)
+	assert.Error(t, err)
+	assert.Nil(t, artifacts)
+
+	// dir - ERROR
+	u, _ = uri.Parse("archive://" + tmpDir)
+	artifacts, err = Artifact(u)
+	assert.Error(t, err)
+	assert.Nil(t, artifacts)
+}
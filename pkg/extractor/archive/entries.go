@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/bundle"
+)
+
+// entries reads the archive named by filename and returns a descriptor for each regular file within it.
+// When the same path appears more than once, the last entry wins (as it does when extracting).
+func entries(filename string, format string) (files []bundle.Descriptor, err error) {
+	idx := make(map[string]int)
+	add := func(name string, src io.Reader) error {
+		relPath, ok := cleanPath(name)
+		// skip manifest and entries without a name
+		if !ok || relPath == bundle.ManifestFilename {
+			return nil
+		}
+		d, err := bundle.NewDescriptor(relPath, src)
+		if err != nil {
+			return err
+		}
+		if i, ok := idx[relPath]; ok {
+			files[i] = *d
+		} else {
+			idx[relPath] = len(files)
+			files = append(files, *d)
+		}
+		return nil
+	}
+
+	files = make([]bundle.Descriptor, 0)
+	switch format {
+	case FormatTar, FormatTarGz:
+		err = readTar(filename, format == FormatTarGz, add)
+	case FormatZip:
+		err = readZip(filename, add)
+	default:
+		err = fmt.Errorf("unsupported archive format: %s", format)
+	}
+	return
+}
+
+func readTar(filename string, gzipped bool, add func(string, io.Reader) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if gzipped {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// skip irregular files (e.g. dir, symlink, pipe, socket, device...)
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if err := add(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func readZip(filename string, add func(string, io.Reader) error) error {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		// skip irregular files (e.g. dir, symlink...)
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = add(zf.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanPath returns the OS agnostic relative path of an archive's entry name.
+// Leading slashes and parent references are resolved against the archive's root.
+func cleanPath(name string) (string, bool) {
+	p := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return "", false
+	}
+	return p, true
+}
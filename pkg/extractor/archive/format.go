@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// Supported archive formats
+const (
+	FormatTar   = "tar"
+	FormatTarGz = "tar+gzip"
+	FormatZip   = "zip"
+)
+
+var contentTypes = map[string]string{
+	FormatTar:   "application/x-tar",
+	FormatTarGz: "application/gzip",
+	FormatZip:   "application/zip",
+}
+
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	zipMagic    = []byte("PK\x03\x04")
+	zipEmpty    = []byte("PK\x05\x06")
+	tarMagic    = []byte("ustar")
+	tarMagicOff = 257
+)
+
+// DetectFormat sniffs the content of the file named by path and returns its archive format.
+// If the file is not a supported archive, an empty string is returned.
+func DetectFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Only the first 512 bytes are needed to sniff the format.
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	buf = buf[:n]
+
+	switch true {
+	case bytes.HasPrefix(buf, zipMagic), bytes.HasPrefix(buf, zipEmpty):
+		return FormatZip, nil
+	case isTar(buf):
+		return FormatTar, nil
+	case bytes.HasPrefix(buf, gzipMagic):
+		// a gzip stream is supported only if it contains a tarball
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", nil
+		}
+		defer zr.Close()
+		n, _ := io.ReadFull(zr, buf[:cap(buf)])
+		if isTar(buf[:n]) {
+			return FormatTarGz, nil
+		}
+	}
+	return "", nil
+}
+
+func isTar(buf []byte) bool {
+	return len(buf) >= tarMagicOff+len(tarMagic) &&
+		bytes.Equal(buf[tarMagicOff:tarMagicOff+len(tarMagic)], tarMagic)
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package checksums
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// Scheme for checksums
+const Scheme = "checksums"
+
+// Algo is the only checksum algorithm supported, since listed hashes are used as artifacts' hashes
+const Algo = "sha256"
+
+var (
+	// BSD format (as produced by `sha256sum --tag` or `shasum -a 256 --tag`): SHA256 (filename) = hash
+	bsdLineRe = regexp.MustCompile(`^([A-Za-z0-9-]+) ?\((.*)\) ?= ?([0-9A-Fa-f]+)$`)
+	// GNU coreutils format: hash, a space, a space or an asterisk (binary mode), filename
+	gnuLineRe = regexp.MustCompile(`^([0-9A-Fa-f]+) [ *](.+)$`)
+)
+
+// Checksum is a single line of a checksums file.
+type Checksum struct {
+	Line int
+	Name string
+	Hash string
+}
+
+// Artifact returns a file *api.Artifact for each checksum listed within the checksums file referenced by u.
+//
+// Artifacts hold the listed hash, named by the listed filename, so the listed files are not needed.
+// The path of the listed file, relative to the checksums file's directory, is also kept within the
+// artifact's metadata (see file.Metadata), so that the file can be checked when available.
+func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
+
+	if u.Scheme != Scheme {
+		return nil, nil
+	}
+
+	path := strings.TrimPrefix(u.Opaque, "//")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	checksums, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	if len(checksums) == 0 {
+		return nil, fmt.Errorf("%s: no checksums found", path)
+	}
+
+	artifacts := make([]*api.Artifact, len(checksums))
+	for i, c := range checksums {
+		listed := c.Name
+		if !filepath.IsAbs(listed) {
+			listed = filepath.Join(filepath.Dir(path), listed)
+		}
+		artifacts[i] = &api.Artifact{
+			Kind: file.Scheme,
+			Name: c.Name,
+			Hash: c.Hash,
+			Metadata: api.Metadata{
+				file.PathKey: listed,
+			},
+		}
+	}
+	return artifacts, nil
+}
+
+// Parse reads the checksums from r, which can be either in GNU coreutils or BSD format
+// (formats can be mixed, like `sha256sum -c` allows). Empty lines and comments are skipped.
+func Parse(r io.Reader) ([]Checksum, error) {
+	checksums := []Checksum{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		s := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(s) == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		c, err := parseLine(s)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		c.Line = line
+		checksums = append(checksums, *c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+func parseLine(s string) (*Checksum, error) {
+	// a leading backslash means the filename is escaped
+	escaped := strings.HasPrefix(s, "\\")
+	if escaped {
+		s = s[1:]
+	}
+
+	var algo, name, hash string
+	if m := bsdLineRe.FindStringSubmatch(s); m != nil {
+		algo, name, hash = strings.ToLower(m[1]), m[2], m[3]
+		if algo != Algo && algo != "sha2-256" {
+			return nil, fmt.Errorf("unsupported checksum algorithm: %s", m[1])
+		}
+	} else if m := gnuLineRe.FindStringSubmatch(s); m != nil {
+		hash, name = m[1], m[2]
+	} else {
+		return nil, fmt.Errorf("improperly formatted checksum line")
+	}
+
+	if len(hash) != 64 {
+		return nil, fmt.Errorf("unsupported checksum length (only %s is supported)", Algo)
+	}
+	if escaped {
+		name = unescape(name)
+	}
+
+	return &Checksum{
+		Name: name,
+		Hash: strings.ToLower(hash),
+	}, nil
+}
+
+// unescape reverts the escaping of filenames containing backslashes or newlines, as done by coreutils.
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package checksums
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+const (
+	hash1 = "181210f8f9c779c26da1d9b2075bde0127302ee0e3fca38c9a83f5b1dd8e5d3b"
+	hash2 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func TestParse(t *testing.T) {
+	content := `# release checksums
+` + hash1 + `  vcn-v0.9.0-linux-amd64
+` + strings.ToUpper(hash2) + ` *vcn-v0.9.0-windows-amd64.exe
+
+SHA256 (vcn-v0.9.0-darwin-amd64) = ` + hash1 + `
+\` + hash2 + `  dir\\with\nnewline
+`
+	checksums, err := Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []Checksum{
+		{Line: 2, Name: "vcn-v0.9.0-linux-amd64", Hash: hash1},
+		{Line: 3, Name: "vcn-v0.9.0-windows-amd64.exe", Hash: hash2},
+		{Line: 5, Name: "vcn-v0.9.0-darwin-amd64", Hash: hash1},
+		{Line: 6, Name: "dir\\with\nnewline", Hash: hash2},
+	}, checksums)
+
+	_, err = Parse(strings.NewReader("not a checksum line\n"))
+	assert.EqualError(t, err, "line 1: improperly formatted checksum line")
+
+	_, err = Parse(strings.NewReader("SHA512 (file) = " + hash1 + hash1 + "\n"))
+	assert.EqualError(t, err, "line 1: unsupported checksum algorithm: SHA512")
+
+	_, err = Parse(strings.NewReader(hash1 + hash1 + "  file\n"))
+	assert.Error(t, err)
+}
+
+func TestArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcn-test-checksums")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "SHA256SUMS")
+	err = ioutil.WriteFile(path, []byte(hash1+"  a.txt\n"+hash2+"  b.txt\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := uri.Parse("checksums://" + path)
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	assert.Equal(t, file.Scheme, artifacts[0].Kind)
+	assert.Equal(t, "a.txt", artifacts[0].Name)
+	assert.Equal(t, hash1, artifacts[0].Hash)
+	assert.Equal(t, "b.txt", artifacts[1].Name)
+	assert.Equal(t, hash2, artifacts[1].Hash)
+	// listed files are resolved relative to the checksums file
+	assert.Equal(t, filepath.Join(dir, "a.txt"), file.Metadata(*artifacts[0]))
+	file.RemoveMetadata(artifacts[0])
+	assert.Empty(t, artifacts[0].Metadata)
+
+	// not a checksums URI
+	u, _ = uri.Parse("file://" + path)
+	artifacts, err = Artifact(u)
+	assert.NoError(t, err)
+	assert.Nil(t, artifacts)
+
+	// empty
+	err = ioutil.WriteFile(path, []byte("# nothing\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ = uri.Parse("checksums://" + path)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
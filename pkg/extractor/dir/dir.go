@@ -10,10 +10,13 @@ package dir
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	digest "github.com/opencontainers/go-digest"
 	"github.com/vchain-us/vcn/pkg/api"
 	"github.com/vchain-us/vcn/pkg/bundle"
 	"github.com/vchain-us/vcn/pkg/extractor"
@@ -23,6 +26,11 @@ import (
 // Scheme for dir
 const Scheme = "dir"
 
+func init() {
+	// options can be given by the URI's query
+	uri.RegisterQueryScheme(Scheme)
+}
+
 // ManifestKey is the metadata's key for storing the manifest
 const ManifestKey = "manifest"
 
@@ -32,6 +40,19 @@ const PathKey = "path"
 type opts struct {
 	initIgnoreFile    bool
 	skipIgnoreFileErr bool
+	gitIgnore         bool
+	jobs              int
+	hashCache         HashCache
+	manifestVersion   uint
+	ignorePatterns    []string
+}
+
+// HashCache is a cache of file digests that can be reused across runs (e.g. *store.HashCache).
+type HashCache interface {
+	// Get returns the cached digest of the file at path, if info still matches the cached one.
+	Get(path string, info os.FileInfo) (digest.Digest, bool)
+	// Put caches the digest d of the file at path, where info are the file's attributes before hashing.
+	Put(path string, info os.FileInfo, d digest.Digest)
 }
 
 // Artifact returns a file *api.Artifact from a given u
@@ -41,10 +62,18 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		return nil, nil
 	}
 
-	opts := &opts{}
+	opts := &opts{
+		manifestVersion: bundle.ManifestSchemaVersion,
+	}
 	if err := extractor.Options(options).Apply(opts); err != nil {
 		return nil, err
 	}
+	if err := opts.applyQuery(u.Query()); err != nil {
+		return nil, err
+	}
+	if opts.manifestVersion != bundle.ManifestSchemaVersion1 && opts.manifestVersion != bundle.ManifestSchemaVersion2 {
+		return nil, fmt.Errorf("unsupported manifest schema version: %d", opts.manifestVersion)
+	}
 
 	path := strings.TrimPrefix(u.Opaque, "//")
 	path, err := filepath.Abs(path)
@@ -75,12 +104,15 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		}
 	}
 
-	files, err := walk(path)
+	files, entries, err := walk(path, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	manifest := bundle.NewManifest(files...)
+	if opts.manifestVersion == bundle.ManifestSchemaVersion2 {
+		manifest = bundle.NewManifestV2(files, entries)
+	}
 	digest, err := manifest.Digest()
 	if err != nil {
 		return nil, err
@@ -100,6 +132,27 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 	}}, nil
 }
 
+// applyQuery sets the options given by the URI's query (i.e. "ignore=<pattern>", which can be repeated,
+// "gitignore=<bool>" and "manifest-version=<version>"), overriding the functional ones.
+func (o *opts) applyQuery(q url.Values) error {
+	o.ignorePatterns = append(o.ignorePatterns, q["ignore"]...)
+	if v := q.Get("gitignore"); v != "" {
+		gitIgnore, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid gitignore query parameter: %s", v)
+		}
+		o.gitIgnore = gitIgnore
+	}
+	if v := q.Get("manifest-version"); v != "" {
+		version, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid manifest-version query parameter: %s", v)
+		}
+		o.manifestVersion = uint(version)
+	}
+	return nil
+}
+
 // WithIgnoreFileInit returns a functional option to instruct the dir's extractor to create the defualt ignore file
 // when not yet present into the targeted directory.
 func WithIgnoreFileInit() extractor.Option {
@@ -121,3 +174,59 @@ func WithSkipIgnoreFileErr() extractor.Option {
 		return nil
 	}
 }
+
+// WithIgnorePatterns returns a functional option to instruct the dir's extractor to ignore the files matching
+// the given patterns (in .vcnignore format), as if they were listed by an ignore file within the root directory.
+// Patterns of the ignore files take precedence.
+func WithIgnorePatterns(patterns ...string) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.ignorePatterns = append(o.ignorePatterns, patterns...)
+		}
+		return nil
+	}
+}
+
+// WithGitIgnore returns a functional option to instruct the dir's extractor to honour .gitignore files
+// as well as ignore files.
+func WithGitIgnore() extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.gitIgnore = true
+		}
+		return nil
+	}
+}
+
+// WithJobs returns a functional option to instruct the dir's extractor to hash up to jobs files concurrently.
+// A value less than or equal to zero means one job per CPU.
+func WithJobs(jobs int) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.jobs = jobs
+		}
+		return nil
+	}
+}
+
+// WithHashCache returns a functional option to instruct the dir's extractor to reuse the digests
+// cached by c for unchanged files, and to cache the digests of the others.
+func WithHashCache(c HashCache) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.hashCache = c
+		}
+		return nil
+	}
+}
+
+// WithManifestVersion returns a functional option to instruct the dir's extractor to use the given
+// manifest schema version (see bundle.ManifestSchemaVersion1 and bundle.ManifestSchemaVersion2).
+func WithManifestVersion(version uint) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.manifestVersion = version
+		}
+		return nil
+	}
+}
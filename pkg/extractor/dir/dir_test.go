@@ -9,11 +9,16 @@
 package dir
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
+	digest "github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/bundle"
 	"github.com/vchain-us/vcn/pkg/uri"
 )
 
@@ -65,3 +70,225 @@ func TestArtifact(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, artifacts)
 }
+
+func TestNestedIgnoreFiles(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TempDir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		IgnoreFilename:                          "*.log\n",
+		"a.log":                                 "",
+		"keep":                                  "",
+		"sub/" + IgnoreFilename:                 "!keep.log\ntmp\n",
+		"sub/keep.log":                          "",
+		"sub/other.log":                         "",
+		"sub/tmp":                               "",
+		"tmp":                                   "",
+		"other/" + GitIgnoreFilename:            "*\n",
+		"other/file":                            "",
+		"sub/deeper/" + IgnoreFilename:          "!tmp\n",
+		"sub/deeper/tmp":                        "",
+		"sub/deeper/" + bundle.ManifestFilename: "",
+	}
+	for name, content := range files {
+		p := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := func(items []bundle.Descriptor) []string {
+		ps := []string{}
+		for _, d := range items {
+			ps = append(ps, d.Paths...)
+		}
+		sort.Strings(ps)
+		return ps
+	}
+
+	// nested ignore files are scoped to their own subtree
+	walked, _, err := walk(tmpDir, &opts{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		IgnoreFilename,
+		"keep",
+		"other/" + GitIgnoreFilename,
+		"other/file",
+		"sub/" + IgnoreFilename,
+		"sub/deeper/" + bundle.ManifestFilename,
+		"sub/deeper/" + IgnoreFilename,
+		"sub/deeper/tmp",
+		"sub/keep.log",
+		"tmp",
+	}, paths(walked))
+
+	// .gitignore files are honoured too, if requested
+	u, _ := uri.Parse("dir://" + tmpDir)
+	withoutGitIgnore, err := Artifact(u)
+	assert.NoError(t, err)
+	withGitIgnore, err := Artifact(u, WithGitIgnore())
+	assert.NoError(t, err)
+	assert.NotEqual(t, withoutGitIgnore[0].Hash, withGitIgnore[0].Hash)
+	manifest, _ := Metadata(*withGitIgnore[0])
+	assert.NotContains(t, paths(manifest.Items), "other/file")
+
+	// and so are the query parameters
+	u, _ = uri.Parse("dir://" + tmpDir + "?gitignore=true")
+	byQuery, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, withGitIgnore[0].Hash, byQuery[0].Hash)
+
+	// additional patterns, overridden by ignore files
+	u, _ = uri.Parse("dir://" + tmpDir + "?ignore=keep*&ignore=other%2F")
+	byQuery, err = Artifact(u)
+	assert.NoError(t, err)
+	manifest, _ = Metadata(*byQuery[0])
+	assert.NotContains(t, paths(manifest.Items), "keep")
+	assert.NotContains(t, paths(manifest.Items), "other/file")
+	assert.Contains(t, paths(manifest.Items), "sub/keep.log")
+	withPatterns, err := Artifact(u, WithIgnorePatterns("tmp"))
+	assert.NoError(t, err)
+	manifest, _ = Metadata(*withPatterns[0])
+	assert.NotContains(t, paths(manifest.Items), "tmp")
+	assert.Contains(t, paths(manifest.Items), "sub/deeper/tmp")
+
+	// invalid query parameters - ERROR
+	u, _ = uri.Parse("dir://" + tmpDir + "?manifest-version=x")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
+
+func TestArtifactWithJobs(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TempDir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for i := 0; i < 50; i++ {
+		p := filepath.Join(tmpDir, fmt.Sprintf("sub%d", i%5), fmt.Sprintf("file%d", i))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(p), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the manifest does not depend on the number of jobs
+	u, _ := uri.Parse("dir://" + tmpDir)
+	sequential, err := Artifact(u, WithJobs(1))
+	assert.NoError(t, err)
+	for _, jobs := range []int{0, 4, 100} {
+		artifacts, err := Artifact(u, WithJobs(jobs))
+		assert.NoError(t, err)
+		assert.Equal(t, sequential[0].Hash, artifacts[0].Hash)
+		assert.Equal(t, sequential[0].Metadata, artifacts[0].Metadata)
+	}
+}
+
+type mapHashCache map[string]digest.Digest
+
+func (c mapHashCache) Get(path string, info os.FileInfo) (digest.Digest, bool) {
+	d, ok := c[path]
+	return d, ok
+}
+
+func (c mapHashCache) Put(path string, info os.FileInfo, d digest.Digest) {
+	c[path] = d
+}
+
+func TestArtifactWithHashCache(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TempDir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpFile := filepath.Join(tmpDir, "file")
+	if err := ioutil.WriteFile(tmpFile, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// digests are cached
+	c := mapHashCache{}
+	u, _ := uri.Parse("dir://" + tmpDir)
+	first, err := Artifact(u, WithHashCache(c))
+	assert.NoError(t, err)
+	assert.Equal(t, digest.FromString("content"), c[tmpFile])
+
+	// and reused, if the cache says so
+	c[tmpFile] = digest.FromString("cached")
+	second, err := Artifact(u, WithHashCache(c))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first[0].Hash, second[0].Hash)
+	manifest, _ := Metadata(*second[0])
+	assert.Equal(t, digest.FromString("cached"), manifest.Items[0].Digest)
+}
+
+func TestArtifactWithManifestVersion2(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TempDir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpFile := filepath.Join(tmpDir, "file")
+	if err := ioutil.WriteFile(tmpFile, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(tmpFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(tmpDir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("file", filepath.Join(tmpDir, "link")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	u, _ := uri.Parse("dir://" + tmpDir)
+	v1, err := Artifact(u)
+	assert.NoError(t, err)
+	v2, err := Artifact(u, WithManifestVersion(bundle.ManifestSchemaVersion2))
+	assert.NoError(t, err)
+	assert.NotEqual(t, v1[0].Hash, v2[0].Hash)
+
+	manifest, _ := Metadata(*v2[0])
+	assert.Equal(t, uint(bundle.ManifestSchemaVersion2), manifest.SchemaVersion)
+	assert.Equal(t, []bundle.Entry{
+		{Path: "empty", Type: bundle.EntryTypeDir, Mode: 0755},
+		{Path: "file", Type: bundle.EntryTypeFile, Mode: 0644},
+		{Path: "link", Type: bundle.EntryTypeSymlink, Target: "file"},
+	}, manifest.Entries)
+
+	// v1 is not affected by modes, symlinks and empty dirs
+	if err := os.Chmod(tmpFile, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, "empty")); err != nil {
+		t.Fatal(err)
+	}
+	v1Changed, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, v1[0].Hash, v1Changed[0].Hash)
+
+	// while v2 is
+	v2Changed, err := Artifact(u, WithManifestVersion(bundle.ManifestSchemaVersion2))
+	assert.NoError(t, err)
+	assert.NotEqual(t, v2[0].Hash, v2Changed[0].Hash)
+
+	// unsupported version
+	_, err = Artifact(u, WithManifestVersion(3))
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ package dir
 import (
 	"github.com/vchain-us/vcn/pkg/api"
 	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
 )
 
 const (
@@ -19,34 +20,10 @@ const (
 
 // Metadata extracts dir related info from a.
 func Metadata(a api.Artifact) (manifest *bundle.Manifest, path string) {
-	if a.Kind != Scheme {
-		return
-	}
-
-	// Get manifest
-	m := a.Metadata[ManifestKey]
-	if m != nil {
-		if mm, ok := m.(*bundle.Manifest); ok {
-			manifest = mm
-		}
-	}
-
-	// Get path
-	p := a.Metadata[PathKey]
-	if p != nil {
-		if pp, ok := p.(string); ok {
-			path = pp
-		}
-	}
-
-	return
+	return extractor.ManifestMetadata(a, Scheme, ManifestKey, PathKey)
 }
 
 // RemoveMetadata removes dir related info from a.
 func RemoveMetadata(a *api.Artifact) {
-	if a == nil || a.Kind != Scheme {
-		return
-	}
-	delete(a.Metadata, ManifestKey)
-	delete(a.Metadata, PathKey)
+	extractor.RemoveManifestMetadata(a, Scheme, ManifestKey, PathKey)
 }
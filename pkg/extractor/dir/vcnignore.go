@@ -23,12 +23,19 @@ import (
 //  - https://git-scm.com/docs/gitignore
 //  - https://github.com/src-d/go-git/blob/master/plumbing/format/gitignore/doc.go
 //
+// Ignore files are honoured at any depth, like git does with nested .gitignore files:
+// patterns of an ignore file are scoped to the directory containing it, and they take
+// precedence over the patterns of ignore files in parent directories.
+//
 // However, this package implementation:
-//  - only uses the ignore file within the root directory (nested ignore files will be treated as normal files)
 //  - always ignores the manifest file (it cannot be excluded by the ignore file)
+//  - does not prune ignored directories, so a file within an ignored directory can still be re-included by a negated pattern
 //
 const IgnoreFilename = ".vcnignore"
 
+// GitIgnoreFilename is the name of the git's ignore file, optionally honoured alongside IgnoreFilename.
+const GitIgnoreFilename = ".gitignore"
+
 // DefaultIgnoreFileContent is the content of ignore file with default patterns.
 const DefaultIgnoreFileContent = `# Windows thumbnail cache files
 Thumbs.db
@@ -84,33 +91,75 @@ const (
 	ignorefileEOL           = "\n"
 )
 
-type nullMatcher struct{}
+// ignoreFileMatcher is a gitignore.Matcher collecting the patterns of the ignore files
+// found while walking a directory tree.
+type ignoreFileMatcher struct {
+	root      string
+	filenames []string
+	patterns  []gitignore.Pattern
+	matcher   gitignore.Matcher
+}
 
-func (n *nullMatcher) Match(path []string, isDir bool) bool {
-	return false
+// newIgnoreFileMatcher returns a matcher for the ignore files named filenames within the root directory.
+// Patterns are loaded by calling load for each directory, parents first.
+func newIgnoreFileMatcher(root string, filenames ...string) *ignoreFileMatcher {
+	return &ignoreFileMatcher{
+		root:      root,
+		filenames: filenames,
+		matcher:   gitignore.NewMatcher(nil),
+	}
 }
 
-// newIgnoreFileMatcher reads and parses the ignore file in path and return a gitignore.Matcher.
-// If the ignore file does not exists, a matcher that will never match is returned instead.
-func newIgnoreFileMatcher(path string) (m gitignore.Matcher, err error) {
-	f, err := os.Open(filepath.Join(path, IgnoreFilename))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &nullMatcher{}, nil
-		}
+// add appends the given patterns, relative to the root.
+func (m *ignoreFileMatcher) add(patterns []string) {
+	if len(patterns) == 0 {
 		return
 	}
-	defer f.Close()
-	ps := []gitignore.Pattern{}
-	if data, err := ioutil.ReadAll(f); err == nil {
-		for _, s := range strings.Split(string(data), ignorefileEOL) {
-			if !strings.HasPrefix(s, ignorefileCommentPrefix) && len(strings.TrimSpace(s)) > 0 {
-				ps = append(ps, gitignore.ParsePattern(s, nil))
+	m.patterns = append(m.patterns, ParseIgnoreFile([]byte(strings.Join(patterns, ignorefileEOL)), nil)...)
+	m.matcher = gitignore.NewMatcher(m.patterns)
+}
+
+// load reads and parses the ignore files (if any) within the directory at relPath, relative to the root.
+// Ignore files are read in order, so patterns of later filenames take precedence.
+func (m *ignoreFileMatcher) load(relPath string) error {
+	var domain []string
+	if relPath != "" && relPath != "." {
+		domain = strings.Split(filepath.ToSlash(relPath), "/")
+	}
+	loaded := false
+	for _, filename := range m.filenames {
+		data, err := ioutil.ReadFile(filepath.Join(m.root, relPath, filename))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
 			}
+			return err
+		}
+		m.patterns = append(m.patterns, ParseIgnoreFile(data, domain)...)
+		loaded = true
+	}
+	if loaded {
+		m.matcher = gitignore.NewMatcher(m.patterns)
+	}
+	return nil
+}
+
+// Match implements gitignore.Matcher.
+func (m *ignoreFileMatcher) Match(path []string, isDir bool) bool {
+	return m.matcher.Match(path, isDir)
+}
+
+// ParseIgnoreFile parses the content of an ignore file and returns its patterns.
+// The domain is the path (split by element) of the directory containing the ignore file,
+// relative to the root directory (nil for the root directory itself).
+func ParseIgnoreFile(data []byte, domain []string) []gitignore.Pattern {
+	ps := []gitignore.Pattern{}
+	for _, s := range strings.Split(string(data), ignorefileEOL) {
+		if !strings.HasPrefix(s, ignorefileCommentPrefix) && len(strings.TrimSpace(s)) > 0 {
+			ps = append(ps, gitignore.ParsePattern(s, domain))
 		}
 	}
-	m = gitignore.NewMatcher(ps)
-	return
+	return ps
 }
 
 // InitIgnoreFile writes the default ignore file if it does not exist.
@@ -10,21 +10,58 @@ package dir
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor/internal/parallel"
 )
 
-func walk(root string) (files []bundle.Descriptor, err error) {
-	files = make([]bundle.Descriptor, 0)
-	ignore, err := newIgnoreFileMatcher(root)
-	if err != nil {
-		return
+// walk returns the descriptors of the regular files within root, not matching the ignore files' patterns.
+// Files are hashed by up to o.jobs concurrent workers (see parallel.Jobs), descriptors are in walk order.
+//
+// For manifest schema version 2, entries for files, symlinks and empty directories are returned too,
+// otherwise entries is nil.
+func walk(root string, o *opts) (files []bundle.Descriptor, entries []bundle.Entry, err error) {
+	ignoreFilenames := []string{IgnoreFilename}
+	if o.gitIgnore {
+		// .vcnignore comes last, so its patterns take precedence
+		ignoreFilenames = []string{GitIgnoreFilename, IgnoreFilename}
 	}
+	ignore := newIgnoreFileMatcher(root, ignoreFilenames...)
+	ignore.add(o.ignorePatterns)
+	withEntries := o.manifestVersion == bundle.ManifestSchemaVersion2
+	paths := make([]string, 0)
+	infos := make([]os.FileInfo, 0)
+	dirs := make([]bundle.Entry, 0)
 	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		// skip irregular files (e.g. dir, symlink, pipe, socket, device...)
-		if !info.Mode().IsRegular() {
+		// load ignore files, if any, before walking the directory's content
+		// (unreadable directories are skipped)
+		if info.IsDir() {
+			if err != nil {
+				return nil
+			}
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if withEntries && relPath != "." {
+				relPath := filepath.ToSlash(relPath)
+				if !ignore.Match(strings.Split(relPath, "/"), true) {
+					dirs = append(dirs, bundle.Entry{
+						Path: relPath,
+						Type: bundle.EntryTypeDir,
+						Mode: bundle.UnixMode(info.Mode()),
+					})
+				}
+			}
+			return ignore.load(relPath)
+		}
+
+		isSymlink := info.Mode()&os.ModeSymlink != 0
+		// skip irregular files (e.g. pipe, socket, device...), and symlinks unless entries are needed
+		if !info.Mode().IsRegular() && !(isSymlink && withEntries) {
 			return nil
 		}
 
@@ -40,18 +77,94 @@ func walk(root string) (files []bundle.Descriptor, err error) {
 			return nil
 		}
 
+		if isSymlink {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, bundle.Entry{
+				Path:   relPath,
+				Type:   bundle.EntryTypeSymlink,
+				Target: filepath.ToSlash(target),
+			})
+			return nil
+		}
+
+		if withEntries {
+			entries = append(entries, bundle.Entry{
+				Path: relPath,
+				Type: bundle.EntryTypeFile,
+				Mode: bundle.UnixMode(info.Mode()),
+			})
+		}
+		paths = append(paths, relPath)
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	if withEntries {
+		entries = append(entries, emptyDirs(dirs, entries)...)
+	}
+
+	files = make([]bundle.Descriptor, len(paths))
+	err = parallel.Do(o.jobs, len(paths), func(i int) error {
+		path := filepath.Join(root, filepath.FromSlash(paths[i]))
+		if o.hashCache != nil {
+			if d, ok := o.hashCache.Get(path, infos[i]); ok {
+				files[i] = bundle.Descriptor{
+					Paths:  []string{paths[i]},
+					Digest: d,
+					Size:   uint64(infos[i].Size()),
+				}
+				return nil
+			}
+		}
+
 		file, err := os.Open(path)
 		if err != nil {
 			return err
 		}
-		d, err := bundle.NewDescriptor(relPath, file)
+		d, err := bundle.NewDescriptor(paths[i], file)
 		file.Close()
 		if err != nil {
 			return err
 		}
-		files = append(files, *d)
+		files[i] = *d
 
+		if o.hashCache != nil {
+			o.hashCache.Put(path, infos[i], d.Digest)
+		}
 		return nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
 	return
 }
+
+// emptyDirs returns the dirs not containing any of entries, nor any other of dirs.
+func emptyDirs(dirs []bundle.Entry, entries []bundle.Entry) []bundle.Entry {
+	nonEmpty := make(map[string]bool)
+	markParents := func(p string) {
+		for p = path.Dir(p); p != "." && !nonEmpty[p]; p = path.Dir(p) {
+			nonEmpty[p] = true
+		}
+	}
+	for _, e := range entries {
+		markParents(e.Path)
+	}
+	for _, d := range dirs {
+		markParents(d.Path)
+	}
+
+	empty := make([]bundle.Entry, 0)
+	for _, d := range dirs {
+		if !nonEmpty[d.Path] {
+			empty = append(empty, d)
+		}
+	}
+	return empty
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package docker
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+const dockerArchiveManifest = "manifest.json"
+
+// dockerArchiveEntry is an item of the manifest.json written by `docker save`.
+type dockerArchiveEntry struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// scanTar calls fn for each regular file within the tarball named by filename.
+func scanTar(filename string, fn func(name string, size int64, r io.Reader) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if err := fn(path.Clean(hdr.Name), hdr.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// readDockerArchive reads the image referenced by arg (i.e. "<file>[:<repo:tag>]") from a `docker save` tarball.
+//
+// The tarball is scanned twice: the first pass reads the manifest,
+// the second one reads the image config and the layers' content size.
+func readDockerArchive(arg string) ([]image, error) {
+	filename, ref := splitRef(arg)
+
+	var entries []dockerArchiveEntry
+	err := scanTar(filename, func(name string, size int64, r io.Reader) error {
+		if name != dockerArchiveManifest {
+			return nil
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("invalid %s: %s", dockerArchiveManifest, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		return nil, fmt.Errorf("%s not found in %s", dockerArchiveManifest, filename)
+	}
+
+	// select the image by ref, if any
+	candidates := make([]dockerArchiveEntry, 0)
+	for _, e := range entries {
+		if ref == "" {
+			candidates = append(candidates, e)
+			continue
+		}
+		for _, t := range e.RepoTags {
+			if t == ref {
+				candidates = append(candidates, e)
+				break
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		if ref != "" {
+			return nil, fmt.Errorf("no image found for ref: %s", ref)
+		}
+		return nil, fmt.Errorf("no image found")
+	case 1:
+	default:
+		return nil, fmt.Errorf("multiple images found, please specify a ref (i.e. %s://<file>:<repo:tag>)", SchemeDockerArchive)
+	}
+	entry := candidates[0]
+
+	layers := make(map[string]bool, len(entry.Layers))
+	for _, l := range entry.Layers {
+		layers[path.Clean(l)] = true
+	}
+
+	var config []byte
+	var size uint64
+	err = scanTar(filename, func(name string, _ int64, r io.Reader) (err error) {
+		switch true {
+		case name == path.Clean(entry.Config):
+			config, err = ioutil.ReadAll(r)
+		case layers[name]:
+			// the size of the layers' content, as `docker inspect` reports
+			var ls uint64
+			if ls, err = layerSize(r); err != nil {
+				return fmt.Errorf("invalid layer %s: %s", name, err)
+			}
+			size += ls
+		}
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("image config %s not found in %s", entry.Config, filename)
+	}
+
+	i, err := newImage(config, entry.RepoTags, size)
+	if err != nil {
+		return nil, err
+	}
+	return []image{*i}, nil
+}
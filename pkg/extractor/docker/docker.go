@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strings"
 
 	"github.com/vchain-us/vcn/pkg/api"
@@ -25,7 +26,54 @@ const Scheme = "docker"
 // SchemePodman is the scheme for podman (Docker-compatible CLI interface)
 const SchemePodman = "podman"
 
-var schemes = map[string]bool{Scheme: true, SchemePodman: true}
+// SchemeOCI is the scheme for OCI image layout directories (no daemon needed)
+const SchemeOCI = "oci"
+
+// SchemeDockerArchive is the scheme for tarballs produced by `docker save` (no daemon needed)
+const SchemeDockerArchive = "docker-archive"
+
+var schemes = map[string]bool{Scheme: true, SchemePodman: true, SchemeOCI: true, SchemeDockerArchive: true}
+
+func init() {
+	// options can be given by the URI's query
+	uri.RegisterQueryScheme(Scheme, SchemePodman, SchemeOCI, SchemeDockerArchive)
+}
+
+type opts struct {
+	platform string
+}
+
+// platform identifies the OS, the architecture and, optionally, the architecture's variant
+// (e.g. "v7" for linux/arm/v7) an image is built for.
+type platform struct {
+	os      string
+	arch    string
+	variant string
+}
+
+// parsePlatform parses s (i.e. "<os>/<arch>[/<variant>]").
+// The current platform is returned if s is empty.
+func parsePlatform(s string) (*platform, error) {
+	if s == "" {
+		return &platform{os: runtime.GOOS, arch: runtime.GOARCH}, nil
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid platform, <os>/<arch>[/<variant>] expected: %s", s)
+	}
+	p := &platform{os: parts[0], arch: parts[1]}
+	if len(parts) == 3 {
+		p.variant = parts[2]
+	}
+	return p, nil
+}
+
+func (p platform) String() string {
+	if p.variant != "" {
+		return p.os + "/" + p.arch + "/" + p.variant
+	}
+	return p.os + "/" + p.arch
+}
 
 // Artifact returns a file *api.Artifact from a given u
 func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
@@ -34,8 +82,28 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		return nil, nil
 	}
 
+	opts := &opts{}
+	if err := extractor.Options(options).Apply(opts); err != nil {
+		return nil, err
+	}
+	if v := u.Query().Get("platform"); v != "" {
+		opts.platform = v
+	}
+	p, err := parsePlatform(opts.platform)
+	if err != nil {
+		return nil, err
+	}
+
 	id := strings.TrimPrefix(u.Opaque, "//")
-	images, err := inspect(u.Scheme, id)
+	var images []image
+	switch u.Scheme {
+	case SchemeOCI:
+		images, err = readOCILayout(id, *p)
+	case SchemeDockerArchive:
+		images, err = readDockerArchive(id)
+	default:
+		images, err = inspect(u.Scheme, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect %s image: %s", u.Scheme, err)
 	}
@@ -44,6 +112,9 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 	}
 
 	i := images[0]
+	if opts.platform != "" && (i.Os != p.os || i.Architecture != p.arch) {
+		return nil, fmt.Errorf("%s image platform is %s/%s, while %s was requested", u.Scheme, i.Os, i.Architecture, p)
+	}
 
 	m := api.Metadata{
 		"architecture": i.Architecture,
@@ -64,6 +135,19 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 	}}, nil
 }
 
+// WithPlatform returns a functional option to instruct the docker's extractor to pick the image for the given
+// platform (i.e. "<os>/<arch>[/<variant>]") out of multi-platform OCI image layouts, and to fail if the image found
+// is built for a different platform.
+// By default, the current platform is picked and no check is made.
+func WithPlatform(platform string) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.platform = platform
+		}
+		return nil
+	}
+}
+
 type image struct {
 	ID            string      `json:"Id"`
 	RepoTags      []string    `json:"RepoTags"`
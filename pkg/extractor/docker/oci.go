@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// OCI image spec media types and annotations,
+// see https://github.com/opencontainers/image-spec
+const (
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	annotationRefName       = "org.opencontainers.image.ref.name"
+	annotationContainerdRef = "io.containerd.image.name"
+)
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// matches returns true if the descriptor's platform is p. The variant is matched only if p has one.
+func (op ociPlatform) matches(p platform) bool {
+	return op.OS == p.os && op.Architecture == p.arch && (p.variant == "" || op.Variant == p.variant)
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        uint64            `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *ociPlatform      `json:"platform,omitempty"`
+}
+
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// imageConfig holds the image configuration fields used by this package.
+// The same JSON format is used by both OCI and Docker images.
+type imageConfig struct {
+	Created       string `json:"created"`
+	Author        string `json:"author"`
+	Architecture  string `json:"architecture"`
+	OS            string `json:"os"`
+	DockerVersion string `json:"docker_version"`
+	Comment       string `json:"comment"`
+}
+
+// newImage returns an image for the given raw config blob, as `docker inspect` would do.
+// The image ID is the digest of the config blob.
+func newImage(config []byte, repoTags []string, size uint64) (*image, error) {
+	c := imageConfig{}
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, fmt.Errorf("invalid image config: %s", err)
+	}
+	if repoTags == nil {
+		repoTags = []string{}
+	}
+	return &image{
+		ID:            digest.SHA256.FromBytes(config).String(),
+		RepoTags:      repoTags,
+		RepoDigests:   []string{},
+		Comment:       c.Comment,
+		Created:       c.Created,
+		DockerVersion: c.DockerVersion,
+		Author:        c.Author,
+		Architecture:  c.Architecture,
+		Os:            c.OS,
+		VirtualSize:   size,
+		Size:          size,
+	}, nil
+}
+
+// splitRef splits an "<path>[:<ref>]" argument. The ref (that may contain colons too) is split off
+// at the first colon preceded by an existing path.
+func splitRef(arg string) (path string, ref string) {
+	if _, err := os.Stat(arg); err == nil {
+		return arg, ""
+	}
+	for i := 1; i < len(arg); i++ {
+		if arg[i] != ':' {
+			continue
+		}
+		if _, err := os.Stat(arg[:i]); err == nil {
+			return arg[:i], arg[i+1:]
+		}
+	}
+	return arg, ""
+}
+
+// layerSize returns the size of the content of the layer read from r, i.e. the sum of the sizes of its
+// tar entries, as Docker computes it when applying the layer. Gzip compressed layers are decompressed.
+func layerSize(r io.Reader) (uint64, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	var lr io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		lr = zr
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return 0, fmt.Errorf("zstd compressed layers are not supported")
+	}
+
+	var size uint64
+	tr := tar.NewReader(lr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		size += uint64(hdr.Size)
+	}
+}
+
+// readOCILayout reads the image referenced by arg (i.e. "<dir>[:<ref>]") from an OCI image layout directory.
+// Multi-platform images are resolved by using p.
+func readOCILayout(arg string, p platform) ([]image, error) {
+	root, ref := splitRef(arg)
+
+	readBlob := func(d ociDescriptor) ([]byte, error) {
+		if err := d.Digest.Validate(); err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadFile(filepath.Join(root, "blobs", d.Digest.Algorithm().String(), d.Digest.Encoded()))
+		if err != nil {
+			return nil, err
+		}
+		// content addressable storage integrity check
+		if d.Digest.Algorithm().FromBytes(data) != d.Digest {
+			return nil, fmt.Errorf("blob integrity check failed: %s", d.Digest)
+		}
+		return data, nil
+	}
+
+	readLayerSize := func(d ociDescriptor) (uint64, error) {
+		if err := d.Digest.Validate(); err != nil {
+			return 0, err
+		}
+		f, err := os.Open(filepath.Join(root, "blobs", d.Digest.Algorithm().String(), d.Digest.Encoded()))
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		verifier := d.Digest.Verifier()
+		size, err := layerSize(io.TeeReader(f, verifier))
+		if err != nil {
+			return 0, fmt.Errorf("invalid layer %s: %s", d.Digest, err)
+		}
+		// the whole blob must be read for the integrity check
+		if _, err := io.Copy(verifier, f); err != nil {
+			return 0, err
+		}
+		if !verifier.Verified() {
+			return 0, fmt.Errorf("blob integrity check failed: %s", d.Digest)
+		}
+		return size, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(root, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	index := ociIndex{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid index.json: %s", err)
+	}
+
+	// select the manifest by ref, if any
+	candidates := make([]ociDescriptor, 0)
+	for _, d := range index.Manifests {
+		if ref == "" || d.Annotations[annotationRefName] == ref || d.Annotations[annotationContainerdRef] == ref {
+			candidates = append(candidates, d)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		if ref != "" {
+			return nil, fmt.Errorf("no image found for ref: %s", ref)
+		}
+		return nil, fmt.Errorf("no image found")
+	case 1:
+	default:
+		return nil, fmt.Errorf("multiple images found, please specify a ref (i.e. %s://<dir>:<ref>)", SchemeOCI)
+	}
+	desc := candidates[0]
+
+	repoTags := []string{}
+	if name := desc.Annotations[annotationContainerdRef]; name != "" {
+		repoTags = append(repoTags, name)
+	} else if name := desc.Annotations[annotationRefName]; name != "" {
+		// usually the ref name is just a tag, so the layout's dir name is used as repository
+		if !strings.Contains(name, ":") {
+			name = filepath.Base(root) + ":" + name
+		}
+		repoTags = append(repoTags, name)
+	}
+
+	// resolve multi-platform images by using the requested (or current) platform, as `docker pull` does
+	for desc.MediaType == mediaTypeOCIIndex || desc.MediaType == mediaTypeDockerManifestList {
+		data, err := readBlob(desc)
+		if err != nil {
+			return nil, err
+		}
+		nested := ociIndex{}
+		if err := json.Unmarshal(data, &nested); err != nil {
+			return nil, fmt.Errorf("invalid image index: %s", err)
+		}
+		found := false
+		for _, d := range nested.Manifests {
+			if d.Platform == nil || d.Platform.matches(p) {
+				desc, found = d, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no image found for platform %s", p)
+		}
+	}
+
+	data, err = readBlob(desc)
+	if err != nil {
+		return nil, err
+	}
+	manifest := ociManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid image manifest: %s", err)
+	}
+
+	config, err := readBlob(manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// the size of the uncompressed layers' content, as `docker inspect` reports
+	var size uint64
+	for _, l := range manifest.Layers {
+		s, err := readLayerSize(l)
+		if err != nil {
+			return nil, err
+		}
+		size += s
+	}
+
+	i, err := newImage(config, repoTags, size)
+	if err != nil {
+		return nil, err
+	}
+	return []image{*i}, nil
+}
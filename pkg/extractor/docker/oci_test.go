@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+var (
+	testConfig       = []byte(`{"architecture":"amd64","os":"linux","created":"2021-01-01T00:00:00Z","config":{}}`)
+	testLayerContent = "layer content"
+	testLayer        = makeLayer(testLayerContent)
+)
+
+// makeLayer returns an uncompressed layer holding a single file with the given content.
+func makeLayer(content string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "dir", Typeflag: tar.TypeDir, Mode: 0755})
+	tw.WriteHeader(&tar.Header{Name: "dir/file", Mode: 0644, Size: int64(len(content))})
+	tw.Write([]byte(content))
+	tw.Close()
+	return buf.Bytes()
+}
+
+func gzipLayer(layer []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(layer)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func writeBlob(t *testing.T, root string, data []byte) ociDescriptor {
+	d := digest.SHA256.FromBytes(data)
+	dir := filepath.Join(root, "blobs", "sha256")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, d.Encoded()), data, 0644))
+	return ociDescriptor{Digest: d, Size: uint64(len(data))}
+}
+
+func TestOCILayout(t *testing.T) {
+	root, err := ioutil.TempDir("", "vcn-test-oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	config := writeBlob(t, root, testConfig)
+	// sizes are the uncompressed content sizes, whether layers are compressed or not
+	layers := []ociDescriptor{writeBlob(t, root, gzipLayer(testLayer)), writeBlob(t, root, makeLayer("other"))}
+	manifest, _ := json.Marshal(ociManifest{Config: config, Layers: layers})
+	md := writeBlob(t, root, manifest)
+	md.MediaType = "application/vnd.oci.image.manifest.v1+json"
+	md.Annotations = map[string]string{annotationRefName: "v1.0"}
+	index, _ := json.Marshal(ociIndex{Manifests: []ociDescriptor{md}})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "index.json"), index, 0644))
+
+	for _, arg := range []string{root, root + ":v1.0"} {
+		u, _ := uri.Parse("oci://" + arg)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 1)
+		assert.Equal(t, SchemeOCI, artifacts[0].Kind)
+		assert.Equal(t, digest.SHA256.FromBytes(testConfig).Encoded(), artifacts[0].Hash)
+		assert.Equal(t, uint64(len(testLayerContent)+len("other")), artifacts[0].Size)
+		assert.Equal(t, "amd64", artifacts[0].Metadata["architecture"])
+		assert.Equal(t, "linux", artifacts[0].Metadata["platform"])
+		assert.Equal(t, "v1.0", artifacts[0].Metadata["version"])
+	}
+
+	// not existing ref - ERROR
+	u, _ := uri.Parse("oci://" + root + ":v2.0")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// tampered layer - ERROR
+	layerPath := filepath.Join(root, "blobs", "sha256", layers[1].Digest.Encoded())
+	assert.NoError(t, ioutil.WriteFile(layerPath, makeLayer("tampered"), 0644))
+	u, _ = uri.Parse("oci://" + root)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+	assert.NoError(t, ioutil.WriteFile(layerPath, makeLayer("other"), 0644))
+
+	// tampered blob - ERROR
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "blobs", "sha256", config.Digest.Encoded()), []byte("{}"), 0644))
+	u, _ = uri.Parse("oci://" + root)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
+
+func TestDockerArchive(t *testing.T) {
+	f, err := ioutil.TempFile("", "vcn-test-docker-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	configName := digest.SHA256.FromBytes(testConfig).Encoded() + ".json"
+	manifest, _ := json.Marshal([]dockerArchiveEntry{{
+		Config:   configName,
+		RepoTags: []string{"hello-world:v1.0"},
+		Layers:   []string{"layer/layer.tar"},
+	}})
+
+	tw := tar.NewWriter(f)
+	for name, data := range map[string][]byte{
+		configName:        testConfig,
+		"layer/layer.tar": testLayer,
+		"manifest.json":   manifest,
+	} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, f.Close())
+
+	for _, arg := range []string{f.Name(), f.Name() + ":hello-world:v1.0"} {
+		u, _ := uri.Parse("docker-archive://" + arg)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 1)
+		assert.Equal(t, SchemeDockerArchive, artifacts[0].Kind)
+		assert.Equal(t, "docker-archive://hello-world:v1.0", artifacts[0].Name)
+		assert.Equal(t, digest.SHA256.FromBytes(testConfig).Encoded(), artifacts[0].Hash)
+		assert.Equal(t, uint64(len(testLayerContent)), artifacts[0].Size)
+		assert.Equal(t, "amd64", artifacts[0].Metadata["architecture"])
+		assert.Equal(t, "linux", artifacts[0].Metadata["platform"])
+		assert.Equal(t, "v1.0", artifacts[0].Metadata["version"])
+	}
+
+	// not existing ref - ERROR
+	u, _ := uri.Parse("docker-archive://" + f.Name() + ":hello-world:v2.0")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// platform mismatch - ERROR
+	u, _ = uri.Parse("docker-archive://" + f.Name() + "?platform=linux/arm64")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+	u, _ = uri.Parse("docker-archive://" + f.Name() + "?platform=linux/amd64")
+	_, err = Artifact(u)
+	assert.NoError(t, err)
+}
+
+func TestOCILayoutPlatform(t *testing.T) {
+	root, err := ioutil.TempDir("", "vcn-test-oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	configs := map[string][]byte{
+		"linux/amd64":  testConfig,
+		"linux/arm64":  []byte(`{"architecture":"arm64","os":"linux","created":"2021-01-01T00:00:00Z","config":{}}`),
+		"linux/arm/v6": []byte(`{"architecture":"arm","variant":"v6","os":"linux","created":"2021-01-01T00:00:00Z","config":{}}`),
+		"linux/arm/v7": []byte(`{"architecture":"arm","variant":"v7","os":"linux","created":"2021-01-01T00:00:00Z","config":{}}`),
+	}
+	nested := ociIndex{}
+	for _, plat := range []string{"linux/amd64", "linux/arm64", "linux/arm/v6", "linux/arm/v7"} {
+		p, _ := parsePlatform(plat)
+		config := writeBlob(t, root, configs[plat])
+		manifest, _ := json.Marshal(ociManifest{Config: config})
+		md := writeBlob(t, root, manifest)
+		md.MediaType = "application/vnd.oci.image.manifest.v1+json"
+		md.Platform = &ociPlatform{OS: p.os, Architecture: p.arch, Variant: p.variant}
+		nested.Manifests = append(nested.Manifests, md)
+	}
+	data, _ := json.Marshal(nested)
+	id := writeBlob(t, root, data)
+	id.MediaType = mediaTypeOCIIndex
+	index, _ := json.Marshal(ociIndex{Manifests: []ociDescriptor{id}})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "index.json"), index, 0644))
+
+	for plat, c := range configs {
+		u, _ := uri.Parse("oci://" + root + "?platform=" + plat)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		assert.Equal(t, digest.SHA256.FromBytes(c).Encoded(), artifacts[0].Hash, plat)
+
+		u, _ = uri.Parse("oci://" + root)
+		artifacts, err = Artifact(u, WithPlatform(plat))
+		assert.NoError(t, err)
+		assert.Equal(t, digest.SHA256.FromBytes(c).Encoded(), artifacts[0].Hash, plat)
+	}
+
+	// without variant, the first image for the architecture is picked
+	u, _ := uri.Parse("oci://" + root + "?platform=linux/arm")
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, digest.SHA256.FromBytes(configs["linux/arm/v6"]).Encoded(), artifacts[0].Hash)
+
+	// not available variant - ERROR
+	u, _ = uri.Parse("oci://" + root + "?platform=linux/arm/v5")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// not available platform - ERROR
+	u, _ = uri.Parse("oci://" + root + "?platform=windows/amd64")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// invalid platform - ERROR
+	u, _ = uri.Parse("oci://" + root + "?platform=linux")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
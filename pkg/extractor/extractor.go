@@ -16,6 +16,8 @@ import (
 
 var extractors = map[string]Extractor{}
 
+var fallback func(scheme string) Extractor
+
 // Extractor extract an api.Artifact referenced by the given uri.URI.
 type Extractor func(*uri.URI, ...Option) ([]*api.Artifact, error)
 
@@ -24,6 +26,13 @@ func Register(scheme string, e Extractor) {
 	extractors[scheme] = e
 }
 
+// RegisterFallback registers f to resolve the Extractor of the schemes that are not registered.
+// f is invoked only when Extract meets such a scheme, and it returns nil if the scheme is not supported.
+// Resolved extractors are registered, so f is invoked once per scheme.
+func RegisterFallback(f func(scheme string) Extractor) {
+	fallback = f
+}
+
 // Schemes returns the list of registered schemes.
 func Schemes() []string {
 	schemes := make([]string, len(extractors))
@@ -43,7 +52,14 @@ func Extract(rawURIs []string, options ...Option) ([]*api.Artifact, error) {
 		if err != nil {
 			return nil, err
 		}
-		if e, ok := extractors[u.Scheme]; ok {
+		e, ok := extractors[u.Scheme]
+		if !ok && fallback != nil {
+			if e = fallback(u.Scheme); e != nil {
+				Register(u.Scheme, e)
+				ok = true
+			}
+		}
+		if ok {
 			ars, err := e(u, options...)
 			if err != nil {
 				return nil, err
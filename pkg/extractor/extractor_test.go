@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package extractor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+func TestExtractFallback(t *testing.T) {
+	defer func() {
+		fallback = nil
+		delete(extractors, "fallback")
+	}()
+
+	calls := 0
+	RegisterFallback(func(scheme string) Extractor {
+		calls++
+		if scheme != "fallback" {
+			return nil
+		}
+		return func(u *uri.URI, options ...Option) ([]*api.Artifact, error) {
+			return []*api.Artifact{{Kind: u.Scheme, Name: u.Opaque}}, nil
+		}
+	})
+
+	artifacts, err := Extract([]string{"fallback://a", "fallback://b"})
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	// resolved once, then registered
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, Schemes(), "fallback")
+
+	_, err = Extract([]string{"unknown://a"})
+	assert.EqualError(t, err, "unknown scheme not yet supported")
+	assert.Equal(t, 2, calls)
+}
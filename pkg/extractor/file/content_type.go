@@ -30,15 +30,19 @@ func contentType(file *os.File) (string, error) {
 		return "", err
 	}
 
+	return sniffContentType(buf), nil
+}
+
+// sniffContentType returns the content type of the given (non-empty) content's head.
+// As for files, buf is expected to be 512 bytes long, zero padded if the content is shorter.
+func sniffContentType(buf []byte) string {
 	kind, err := filetype.Match(buf)
 	if err == nil && kind != filetype.Unknown {
-		return kind.MIME.Value, nil
+		return kind.MIME.Value
 	}
 
 	// As fallback, use the net/http package's handy DectectContentType function.
 	// Always returns a valid content-type by returning "application/octet-stream"
 	// if no others seemed to match.
-	contentType := http.DetectContentType(buf)
-
-	return contentType, nil
+	return http.DetectContentType(buf)
 }
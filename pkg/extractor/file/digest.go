@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package file
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"golang.org/x/crypto/sha3"
+)
+
+// DigestsKey is the metadata's key for storing the additional digests of the file, by algorithm
+const DigestsKey = "digests"
+
+// PathKey is the metadata's key for the file path
+const PathKey = "path"
+
+// PrimaryDigestAlgo is the algorithm of the digest used as the artifact's hash
+const PrimaryDigestAlgo = "sha256"
+
+var digestAlgos = map[string]func() hash.Hash{
+	"sha256":   sha256.New,
+	"sha384":   sha512.New384,
+	"sha512":   sha512.New,
+	"sha3-256": sha3.New256,
+	"sha3-384": sha3.New384,
+	"sha3-512": sha3.New512,
+}
+
+// DigestAlgos returns the sorted list of the supported digest algorithms.
+func DigestAlgos() []string {
+	algos := make([]string, 0, len(digestAlgos))
+	for algo := range digestAlgos {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+	return algos
+}
+
+// digester computes the primary digest and any additional digest in a single pass.
+type digester struct {
+	primary hash.Hash
+	extra   map[string]hash.Hash
+}
+
+// newDigester returns a digester for the given additional algos.
+// The primary algorithm is always computed, so it is ignored if listed within algos.
+func newDigester(algos []string) (*digester, error) {
+	d := &digester{
+		primary: digestAlgos[PrimaryDigestAlgo](),
+		extra:   make(map[string]hash.Hash, len(algos)),
+	}
+	for _, algo := range algos {
+		algo = strings.ToLower(strings.TrimSpace(algo))
+		if algo == PrimaryDigestAlgo {
+			continue
+		}
+		newHash, ok := digestAlgos[algo]
+		if !ok {
+			return nil, fmt.Errorf("unsupported digest algorithm: %s (supported: %s)", algo, strings.Join(DigestAlgos(), ", "))
+		}
+		d.extra[algo] = newHash()
+	}
+	return d, nil
+}
+
+// Write implements io.Writer, feeding all hashes.
+func (d *digester) Write(p []byte) (int, error) {
+	d.primary.Write(p)
+	for _, h := range d.extra {
+		h.Write(p)
+	}
+	return len(p), nil
+}
+
+// Primary returns the hex encoded primary digest.
+func (d *digester) Primary() string {
+	return hex.EncodeToString(d.primary.Sum(nil))
+}
+
+// Extra returns the hex encoded additional digests, by algorithm, or nil if none.
+func (d *digester) Extra() map[string]interface{} {
+	if len(d.extra) == 0 {
+		return nil
+	}
+	digests := make(map[string]interface{}, len(d.extra))
+	for algo, h := range d.extra {
+		digests[algo] = hex.EncodeToString(h.Sum(nil))
+	}
+	return digests
+}
+
+// Digests returns the additional digests recorded within m, by algorithm.
+func Digests(m api.Metadata) map[string]string {
+	digests := map[string]string{}
+	switch v := m.Get(DigestsKey, nil).(type) {
+	case map[string]interface{}:
+		for algo, d := range v {
+			if s, ok := d.(string); ok {
+				digests[algo] = s
+			}
+		}
+	case map[string]string:
+		for algo, d := range v {
+			digests[algo] = d
+		}
+	}
+	return digests
+}
+
+// CheckDigests computes the digests of the file at path for each algorithm recorded
+// by expected and returns an error if any of them does not match.
+func CheckDigests(path string, expected map[string]string) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	algos := make([]string, 0, len(expected))
+	for algo := range expected {
+		algos = append(algos, algo)
+	}
+
+	d, err := newDigester(algos)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(d, f); err != nil {
+		return err
+	}
+
+	actual := map[string]string{PrimaryDigestAlgo: d.Primary()}
+	for algo, digest := range d.Extra() {
+		actual[algo] = digest.(string)
+	}
+	return MatchDigests(expected, actual)
+}
+
+// MatchDigests returns an error if any of the expected digests is missing from actual or does not match.
+func MatchDigests(expected map[string]string, actual map[string]string) error {
+	algos := make([]string, 0, len(expected))
+	for algo := range expected {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+	for _, algo := range algos {
+		digest, ok := actual[algo]
+		if !ok {
+			return fmt.Errorf("%s digest is not available", algo)
+		}
+		if digest != strings.ToLower(expected[algo]) {
+			return fmt.Errorf("%s digest mismatch", algo)
+		}
+	}
+	return nil
+}
+
+// Metadata returns the path of the file from which a has been extracted, if any.
+func Metadata(a api.Artifact) (path string) {
+	if a.Kind != Scheme {
+		return
+	}
+	if p, ok := a.Metadata[PathKey].(string); ok {
+		path = p
+	}
+	return
+}
+
+// RemoveMetadata removes file related info from a, if any.
+// Only the path is removed, since the digests are meant to be notarized.
+func RemoveMetadata(a *api.Artifact) {
+	if a == nil || a.Kind != Scheme {
+		return
+	}
+	delete(a.Metadata, PathKey)
+}
@@ -9,10 +9,9 @@
 package file
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/vchain-us/vcn/pkg/api"
@@ -23,6 +22,10 @@ import (
 // Scheme for file
 const Scheme = "file"
 
+type opts struct {
+	digests []string
+}
+
 // Artifact returns a file *api.Artifact from a given u
 func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
 
@@ -30,8 +33,18 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		return nil, nil
 	}
 
+	opts := &opts{}
+	if err := extractor.Options(options).Apply(opts); err != nil {
+		return nil, err
+	}
+
 	path := strings.TrimPrefix(u.Opaque, "//")
 
+	d, err := newDigester(opts.digests)
+	if err != nil {
+		return nil, err
+	}
+
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -42,11 +55,17 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 	m := api.Metadata{}
 
 	// Hash
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
+	if _, err := io.Copy(d, f); err != nil {
 		return nil, err
 	}
-	checksum := h.Sum(nil)
+	if digests := d.Extra(); digests != nil {
+		m[DigestsKey] = digests
+	}
+
+	// Path, used for checking the additional digests
+	if p, err := filepath.Abs(path); err == nil {
+		m[PathKey] = p
+	}
 
 	// Name and Size
 	stat, err := f.Stat()
@@ -70,12 +89,30 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		m.SetValues(data)
 	}
 
+	// Sniff package info, if any
+	name := stat.Name()
+	if ok, data, pkgName := pkgInfo(f); ok {
+		m.SetValues(data)
+		name = pkgName
+	}
+
 	return []*api.Artifact{{
 		Kind:        Scheme,
-		Name:        stat.Name(),
-		Hash:        hex.EncodeToString(checksum),
+		Name:        name,
+		Hash:        d.Primary(),
 		Size:        uint64(stat.Size()),
 		ContentType: ct,
 		Metadata:    m,
 	}}, nil
 }
+
+// WithDigests computes the digests of the given algos in addition to the primary one
+// and records them into the artifact's metadata
+func WithDigests(algos ...string) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.digests = append(o.digests, algos...)
+		}
+		return nil
+	}
+}
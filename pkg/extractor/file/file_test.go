@@ -9,9 +9,15 @@
 package file
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"path/filepath"
+	"strings"
 
 	"github.com/stretchr/testify/assert"
 
@@ -41,3 +47,104 @@ func TestFile(t *testing.T) {
 	assert.Equal(t, "181210f8f9c779c26da1d9b2075bde0127302ee0e3fca38c9a83f5b1dd8e5d3b", artifacts[0].Hash)
 
 }
+
+func TestFileWithDigests(t *testing.T) {
+	file, err := ioutil.TempFile("", "vcn-test-scheme-file")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	err = ioutil.WriteFile(file.Name(), []byte("123\n"), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	u, _ := uri.Parse("file://" + file.Name())
+
+	artifacts, err := Artifact(u, WithDigests("sha256", "SHA512", "sha3-256"))
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+	assert.Equal(t, "181210f8f9c779c26da1d9b2075bde0127302ee0e3fca38c9a83f5b1dd8e5d3b", artifacts[0].Hash)
+	digests := Digests(artifacts[0].Metadata)
+	assert.Equal(t, map[string]string{
+		"sha512":   "ea2fe56bb8c1fb5ada84963b42ed71b764a74b092d75755173ade06f2f4aada9c00d6c302e185035cbe85fdff31698bca93e8661f0cbcef52cf2ff65864fd742",
+		"sha3-256": "aba7aeb8a7948dd0cdb8eeb9239e5d1dab2bd840f13930f86f6e67ba40ea5350",
+	}, digests)
+
+	path := Metadata(*artifacts[0])
+	assert.Equal(t, file.Name(), path)
+	RemoveMetadata(artifacts[0])
+	assert.Empty(t, Metadata(*artifacts[0]))
+	assert.NotNil(t, artifacts[0].Metadata[DigestsKey])
+
+	// recorded digests match
+	assert.NoError(t, CheckDigests(path, digests))
+
+	// content changed
+	err = ioutil.WriteFile(file.Name(), []byte("1234\n"), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	assert.Error(t, CheckDigests(path, digests))
+
+	// unsupported algorithm
+	_, err = Artifact(u, WithDigests("md5"))
+	assert.Error(t, err)
+}
+
+func TestReaderArtifact(t *testing.T) {
+	artifacts, err := ReaderArtifact(strings.NewReader("123\n"), "artifact-v1.2.3.bin", WithDigests("sha512"))
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+	assert.Equal(t, Scheme, artifacts[0].Kind)
+	assert.Equal(t, "artifact-v1.2.3.bin", artifacts[0].Name)
+	assert.Equal(t, "181210f8f9c779c26da1d9b2075bde0127302ee0e3fca38c9a83f5b1dd8e5d3b", artifacts[0].Hash)
+	assert.Equal(t, uint64(4), artifacts[0].Size)
+	assert.Equal(t, "application/octet-stream", artifacts[0].ContentType)
+	assert.Equal(t, "1.2.3", artifacts[0].Metadata["version"])
+	assert.Equal(t, map[string]string{
+		"sha512": "ea2fe56bb8c1fb5ada84963b42ed71b764a74b092d75755173ade06f2f4aada9c00d6c302e185035cbe85fdff31698bca93e8661f0cbcef52cf2ff65864fd742",
+	}, Digests(artifacts[0].Metadata))
+	assert.Empty(t, Metadata(*artifacts[0]))
+
+	// longer than the sniffed head
+	content := bytes.Repeat([]byte("%PDF-1.4\n"), 100)
+	artifacts, err = ReaderArtifact(bytes.NewReader(content), "")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(content)), artifacts[0].Size)
+	assert.Equal(t, "application/pdf", artifacts[0].ContentType)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(content)), artifacts[0].Hash)
+
+	// empty
+	artifacts, err = ReaderArtifact(strings.NewReader(""), "")
+	assert.NoError(t, err)
+	assert.Empty(t, artifacts[0].ContentType)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", artifacts[0].Hash)
+}
+
+func TestFilePackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcn-test-scheme-file")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := &bytes.Buffer{}
+	gz := gzip.NewWriter(b)
+	tw := tar.NewWriter(gz)
+	content := []byte(`{"name": "vcn", "version": "0.9.0-rc.1"}`)
+	tw.WriteHeader(&tar.Header{Name: "package/package.json", Mode: 0644, Size: int64(len(content))})
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+	path := filepath.Join(dir, "vcn-v0.9.0.tgz")
+	if err := ioutil.WriteFile(path, b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	u, _ := uri.Parse("file://" + path)
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "vcn", artifacts[0].Name)
+	assert.Equal(t, "0.9.0-rc.1", artifacts[0].Metadata["version"])
+	assert.NotNil(t, artifacts[0].Metadata[PackageKey])
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const arMagic = "!<arch>\n"
+
+// Deb returns the info declared by the control file of a Debian binary package.
+// Control archives can be uncompressed, or compressed by gzip, xz or zstd (as dpkg-deb allows).
+func Deb(file *os.File) (*Data, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(file)
+
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != arMagic {
+		return nil, errors.New("not a deb file")
+	}
+
+	// ar members: 60 bytes header followed by data, padded to an even size
+	header := make([]byte, 60)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("control archive not found")
+			}
+			return nil, err
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ar header: %s", err)
+		}
+		if strings.HasPrefix(name, "control.tar") {
+			control, err := debControl(io.LimitReader(r, size), strings.TrimPrefix(name, "control.tar"))
+			if err != nil {
+				return nil, err
+			}
+			return debData(control), nil
+		}
+		if _, err := io.CopyN(ioutil.Discard, r, size+size%2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// debControl returns the content of the control file within the control archive.
+func debControl(r io.Reader, compression string) ([]byte, error) {
+	switch compression {
+	case "":
+	case ".gz":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case ".xz":
+		xzr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = xzr
+	case ".zst":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported control archive compression: %s", compression)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("control file not found")
+			}
+			return nil, err
+		}
+		if path.Clean(h.Name) == "control" {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+func debData(control []byte) *Data {
+	fields := parseFields(control, "\n")
+	d := &Data{
+		Format:  "deb",
+		Name:    get(fields, "Package"),
+		Version: get(fields, "Version"),
+		Arch:    get(fields, "Architecture"),
+	}
+	for _, key := range []string{"Pre-Depends", "Depends"} {
+		for _, dep := range strings.Split(get(fields, key), ",") {
+			if dep = strings.TrimSpace(dep); dep != "" {
+				d.Dependencies = append(d.Dependencies, dep)
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const jarManifest = "META-INF/MANIFEST.MF"
+
+type pom struct {
+	Dependencies []struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Scope      string `xml:"scope"`
+	} `xml:"dependencies>dependency"`
+}
+
+// Jar returns the info declared by a Java archive.
+//
+// Maven coordinates (groupId:artifactId) and dependencies are used when the archive embeds
+// its Maven metadata, otherwise name and version are read from the manifest (OSGi bundle or
+// implementation's attributes).
+func Jar(file *os.File) (*Data, error) {
+	zr, err := openZip(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// shaded archives embed the Maven metadata of other artifacts too
+	var poms []*zip.File
+	var manifest *zip.File
+	for _, f := range zr.File {
+		if f.Name == jarManifest {
+			manifest = f
+		}
+		if strings.HasPrefix(f.Name, "META-INF/maven/") && path.Base(f.Name) == "pom.properties" {
+			poms = append(poms, f)
+		}
+	}
+
+	d := &Data{Format: "jar"}
+
+	if f := jarPom(poms, filepath.Base(file.Name())); f != nil {
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		props := parseProperties(data)
+		d.Name = props["groupId"] + ":" + props["artifactId"]
+		d.Version = props["version"]
+		if f := zipFile(zr, path.Join(path.Dir(f.Name), "pom.xml")); f != nil {
+			if data, err := readZipFile(f); err == nil {
+				d.Dependencies = pomDependencies(data)
+			}
+		}
+		return d, nil
+	}
+
+	if manifest == nil {
+		return nil, errors.New("manifest not found")
+	}
+	data, err := readZipFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+	fields := parseFields(data, "")
+	for _, key := range []string{"Bundle-SymbolicName", "Automatic-Module-Name", "Implementation-Title"} {
+		if name := get(fields, key); name != "" {
+			// drop directives, e.g. "singleton:=true"
+			d.Name = strings.TrimSpace(strings.SplitN(name, ";", 2)[0])
+			break
+		}
+	}
+	for _, key := range []string{"Bundle-Version", "Implementation-Version"} {
+		if version := get(fields, key); version != "" {
+			d.Version = version
+			break
+		}
+	}
+	return d, nil
+}
+
+// jarPom returns the pom.properties of the archive's own artifact: the only one, or the one whose
+// artifactId prefixes the archive's filename.
+func jarPom(poms []*zip.File, filename string) *zip.File {
+	if len(poms) == 1 {
+		return poms[0]
+	}
+	for _, f := range poms {
+		// META-INF/maven/<groupId>/<artifactId>/pom.properties
+		artifactID := path.Base(path.Dir(f.Name))
+		if strings.HasPrefix(filename, artifactID+"-") {
+			return f
+		}
+	}
+	return nil
+}
+
+func zipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// parseProperties parses the "key=value" lines of a Java properties file, skipping comments.
+func parseProperties(data []byte) map[string]string {
+	props := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		if i := strings.IndexAny(line, "=:"); i > 0 {
+			props[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return props
+}
+
+// pomDependencies returns the dependencies declared by a pom.xml as groupId:artifactId[:version],
+// test dependencies excluded.
+func pomDependencies(data []byte) []string {
+	var p pom
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil
+	}
+	var deps []string
+	for _, dep := range p.Dependencies {
+		if dep.Scope == "test" {
+			continue
+		}
+		coords := dep.GroupID + ":" + dep.ArtifactID
+		if dep.Version != "" {
+			coords += ":" + dep.Version
+		}
+		deps = append(deps, coords)
+	}
+	return deps
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// npmPackageJSON is the path of package.json within npm tarballs, as produced by `npm pack`
+const npmPackageJSON = "package/package.json"
+
+type packageJSON struct {
+	Name         string            `json:"name"`
+	Version      string            `json:"version"`
+	CPU          []string          `json:"cpu"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// NPM returns the info declared by the package.json of a npm tarball.
+func NPM(file *os.File) (*Data, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("package.json not found")
+			}
+			return nil, err
+		}
+		if strings.TrimPrefix(h.Name, "./") != npmPackageJSON {
+			continue
+		}
+		var p packageJSON
+		if err := json.NewDecoder(tr).Decode(&p); err != nil {
+			return nil, err
+		}
+		d := &Data{
+			Format:  "npm",
+			Name:    p.Name,
+			Version: p.Version,
+			Arch:    strings.Join(p.CPU, ","),
+		}
+		for name, version := range p.Dependencies {
+			d.Dependencies = append(d.Dependencies, name+"@"+version)
+		}
+		sort.Strings(d.Dependencies)
+		return d, nil
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Data is the info declared by a package file.
+type Data struct {
+	Format       string   `json:"format"`
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Arch         string   `json:"arch,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+var sniffers = map[string]func(*os.File) (*Data, error){
+	".deb":  Deb,
+	".udeb": Deb,
+	".rpm":  RPM,
+	".whl":  Wheel,
+	".jar":  Jar,
+	".war":  Jar,
+	".ear":  Jar,
+	".tgz":  NPM,
+}
+
+// File returns the info declared by file, if it is a supported package file.
+// The package format is chosen by the file extension.
+func File(file *os.File) (*Data, error) {
+	sniffer, ok := sniffers[strings.ToLower(filepath.Ext(file.Name()))]
+	if !ok {
+		return nil, errors.New("not a package file")
+	}
+	d, err := sniffer(file)
+	if err != nil {
+		return nil, err
+	}
+	if d.Name == "" {
+		return nil, errors.New("package name not found")
+	}
+	return d, nil
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+// parseFields parses the "Key: value" fields of data up to the first empty line, as used by
+// Debian control files, Python core metadata and JAR manifests.
+// Continuation lines start with a space and are appended to the previous value by using join.
+func parseFields(data []byte, join string) []field {
+	fields := []field{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			last := &fields[len(fields)-1]
+			if join == "" {
+				last.value += line[1:]
+			} else {
+				last.value += join + strings.TrimSpace(line)
+			}
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		fields = append(fields, field{
+			key:   strings.TrimSpace(line[:i]),
+			value: strings.TrimSpace(line[i+1:]),
+		})
+	}
+	return fields
+}
+
+// get returns the value of the first field named key (case insensitive), if any.
+func get(fields []field, key string) string {
+	for _, f := range fields {
+		if strings.EqualFold(f.key, key) {
+			return f.value
+		}
+	}
+	return ""
+}
+
+// getAll returns the values of all the fields named key (case insensitive).
+func getAll(fields []field, key string) []string {
+	values := []string{}
+	for _, f := range fields {
+		if strings.EqualFold(f.key, key) {
+			values = append(values, f.value)
+		}
+	}
+	return values
+}
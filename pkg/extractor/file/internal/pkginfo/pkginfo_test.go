@@ -0,0 +1,300 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+func writeTemp(t *testing.T, name string, data []byte) *os.File {
+	dir, err := ioutil.TempDir("", "vcn-test-pkginfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	b := &bytes.Buffer{}
+	tw := tar.NewWriter(b)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	return b.Bytes()
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	b := &bytes.Buffer{}
+	gz := gzip.NewWriter(b)
+	gz.Write(tarball(t, files))
+	gz.Close()
+	return b.Bytes()
+}
+
+func tarXz(t *testing.T, files map[string]string) []byte {
+	b := &bytes.Buffer{}
+	xzw, err := xz.NewWriter(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xzw.Write(tarball(t, files))
+	xzw.Close()
+	return b.Bytes()
+}
+
+func tarZst(t *testing.T, files map[string]string) []byte {
+	b := &bytes.Buffer{}
+	zw, err := zstd.NewWriter(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Write(tarball(t, files))
+	zw.Close()
+	return b.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	b := &bytes.Buffer{}
+	zw := zip.NewWriter(b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return b.Bytes()
+}
+
+func TestDeb(t *testing.T) {
+	files := map[string]string{
+		"./control": `Package: vcn
+Version: 1:0.9.0-1
+Architecture: amd64
+Maintainer: vChain <info@vchain.us>
+Pre-Depends: dpkg (>= 1.17)
+Depends: libc6 (>= 2.14), ca-certificates
+Description: vChain CodeNotary
+ notarize and authenticate
+`,
+	}
+	deb := func(controlName string, control []byte) []byte {
+		ar := &bytes.Buffer{}
+		ar.WriteString(arMagic)
+		member := func(name string, data []byte) {
+			fmt.Fprintf(ar, "%-16s%-12s%-6s%-6s%-8s%-10d`\n", name, "0", "0", "0", "100644", len(data))
+			ar.Write(data)
+			if len(data)%2 == 1 {
+				ar.WriteByte('\n')
+			}
+		}
+		member("debian-binary", []byte("2.0\n"))
+		member(controlName, control)
+		member("data.tar.gz", tarGz(t, nil))
+		return ar.Bytes()
+	}
+
+	for name, control := range map[string][]byte{
+		"control.tar":     tarball(t, files),
+		"control.tar.gz":  tarGz(t, files),
+		"control.tar.xz":  tarXz(t, files),
+		"control.tar.zst": tarZst(t, files),
+	} {
+		d, err := File(writeTemp(t, "vcn_0.9.0-1_amd64.deb", deb(name, control)))
+		assert.NoError(t, err, name)
+		assert.Equal(t, &Data{
+			Format:       "deb",
+			Name:         "vcn",
+			Version:      "1:0.9.0-1",
+			Arch:         "amd64",
+			Dependencies: []string{"dpkg (>= 1.17)", "libc6 (>= 2.14)", "ca-certificates"},
+		}, d, name)
+	}
+
+	// unsupported compression - ERROR
+	_, err := File(writeTemp(t, "vcn_0.9.0-1_amd64.deb", deb("control.tar.bz2", tarball(t, files))))
+	assert.Error(t, err)
+
+	_, err = File(writeTemp(t, "bad.deb", []byte("not a deb")))
+	assert.Error(t, err)
+}
+
+func TestRPM(t *testing.T) {
+	type tag struct {
+		id    uint32
+		typ   uint32
+		value interface{}
+	}
+	header := func(tags []tag) []byte {
+		index := &bytes.Buffer{}
+		store := &bytes.Buffer{}
+		for _, tg := range tags {
+			count := 1
+			offset := store.Len()
+			switch v := tg.value.(type) {
+			case string:
+				store.WriteString(v + "\x00")
+			case []string:
+				count = len(v)
+				for _, s := range v {
+					store.WriteString(s + "\x00")
+				}
+			case []uint32:
+				count = len(v)
+				binary.Write(store, binary.BigEndian, v)
+			}
+			binary.Write(index, binary.BigEndian, []uint32{tg.id, tg.typ, uint32(offset), uint32(count)})
+		}
+		b := &bytes.Buffer{}
+		b.Write(rpmHeaderMagic)
+		b.Write(make([]byte, 4))
+		binary.Write(b, binary.BigEndian, []uint32{uint32(len(tags)), uint32(store.Len())})
+		b.Write(index.Bytes())
+		b.Write(store.Bytes())
+		return b.Bytes()
+	}
+
+	rpm := &bytes.Buffer{}
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, rpmLeadMagic)
+	rpm.Write(lead)
+	sig := header([]tag{{1000, rpmTypeInt32, []uint32{42}}})
+	rpm.Write(sig)
+	rpm.Write(make([]byte, (8-len(sig)%8)%8))
+	rpm.Write(header([]tag{
+		{rpmTagName, rpmTypeString, "vcn"},
+		{rpmTagVersion, rpmTypeString, "0.9.0"},
+		{rpmTagRelease, rpmTypeString, "1.el8"},
+		{rpmTagArch, rpmTypeString, "x86_64"},
+		{rpmTagRequireFlags, rpmTypeInt32, []uint32{rpmSenseGreater | rpmSenseEqual, 0, rpmSenseLess | rpmSenseEqual}},
+		{rpmTagRequireName, rpmTypeStringArray, []string{"glibc", "/bin/sh", "rpmlib(CompressedFileNames)"}},
+		{rpmTagRequireVersion, rpmTypeStringArray, []string{"2.17", "", "3.0.4-1"}},
+	}))
+
+	d, err := File(writeTemp(t, "vcn-0.9.0-1.el8.x86_64.rpm", rpm.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, &Data{
+		Format:       "rpm",
+		Name:         "vcn",
+		Version:      "0.9.0-1.el8",
+		Arch:         "x86_64",
+		Dependencies: []string{"glibc >= 2.17", "/bin/sh"},
+	}, d)
+}
+
+func TestWheel(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"vcn/__init__.py": "",
+		"vcn-0.9.0.dist-info/METADATA": `Metadata-Version: 2.1
+Name: vcn
+Version: 0.9.0
+Requires-Dist: requests (>=2.0)
+Requires-Dist: pyyaml
+
+Long description
+Name: not-a-field
+`,
+		"vcn-0.9.0.dist-info/WHEEL": "Wheel-Version: 1.0\nTag: py3-none-manylinux1_x86_64\n",
+	})
+
+	d, err := File(writeTemp(t, "vcn-0.9.0-py3-none-manylinux1_x86_64.whl", data))
+	assert.NoError(t, err)
+	assert.Equal(t, &Data{
+		Format:       "wheel",
+		Name:         "vcn",
+		Version:      "0.9.0",
+		Arch:         "manylinux1_x86_64",
+		Dependencies: []string{"requests (>=2.0)", "pyyaml"},
+	}, d)
+}
+
+func TestJar(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"META-INF/MANIFEST.MF":                        "Manifest-Version: 1.0\nImplementation-Title: vcn\n",
+		"META-INF/maven/us.vchain/vcn/pom.properties": "#Generated by Maven\ngroupId=us.vchain\nartifactId=vcn\nversion=0.9.0\n",
+		"META-INF/maven/us.vchain/vcn/pom.xml": `<project>
+  <dependencies>
+    <dependency><groupId>com.google.code.gson</groupId><artifactId>gson</artifactId><version>2.8.6</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`,
+		"META-INF/maven/com.google.code.gson/gson/pom.properties": "groupId=com.google.code.gson\nartifactId=gson\nversion=2.8.6\n",
+	})
+	d, err := File(writeTemp(t, "vcn-0.9.0-shaded.jar", data))
+	assert.NoError(t, err)
+	assert.Equal(t, &Data{
+		Format:       "jar",
+		Name:         "us.vchain:vcn",
+		Version:      "0.9.0",
+		Dependencies: []string{"com.google.code.gson:gson:2.8.6"},
+	}, d)
+
+	// manifest only, with a continuation line
+	data = zipArchive(t, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nBundle-SymbolicName: us.vchain.v\r\n cn;singleton:=true\r\nBundle-Version: 0.9.0\r\n",
+	})
+	d, err = File(writeTemp(t, "vcn.jar", data))
+	assert.NoError(t, err)
+	assert.Equal(t, &Data{
+		Format:  "jar",
+		Name:    "us.vchain.vcn",
+		Version: "0.9.0",
+	}, d)
+}
+
+func TestNPM(t *testing.T) {
+	data := tarGz(t, map[string]string{
+		"package/package.json": `{"name": "@vchain/vcn", "version": "0.9.0", "cpu": ["x64"], "dependencies": {"yaml": "^1.10.0", "axios": "0.21.1"}}`,
+	})
+	d, err := File(writeTemp(t, "vchain-vcn-0.9.0.tgz", data))
+	assert.NoError(t, err)
+	assert.Equal(t, &Data{
+		Format:       "npm",
+		Name:         "@vchain/vcn",
+		Version:      "0.9.0",
+		Arch:         "x64",
+		Dependencies: []string{"axios@0.21.1", "yaml@^1.10.0"},
+	}, d)
+
+	// not a npm tarball
+	_, err = File(writeTemp(t, "other.tgz", tarGz(t, map[string]string{"README": "hello"})))
+	assert.Error(t, err)
+
+	// unsupported extension
+	_, err = File(writeTemp(t, "vchain-vcn-0.9.0.tar.gz", data))
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	rpmLeadSize = 96
+
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+
+	rpmTagName           = 1000
+	rpmTagVersion        = 1001
+	rpmTagRelease        = 1002
+	rpmTagEpoch          = 1003
+	rpmTagArch           = 1022
+	rpmTagRequireFlags   = 1048
+	rpmTagRequireName    = 1049
+	rpmTagRequireVersion = 1050
+
+	rpmSenseLess    = 0x02
+	rpmSenseGreater = 0x04
+	rpmSenseEqual   = 0x08
+)
+
+var (
+	rpmLeadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+// rpmHeader is a parsed RPM header structure, made of index entries and the data store.
+type rpmHeader struct {
+	entries map[uint32]rpmEntry
+	store   []byte
+}
+
+type rpmEntry struct {
+	typ    uint32
+	offset uint32
+	count  uint32
+}
+
+// RPM returns the info declared by the header of an RPM package.
+func RPM(file *os.File) (*Data, error) {
+	lead := make([]byte, rpmLeadSize)
+	if _, err := file.ReadAt(lead, 0); err != nil || !bytes.Equal(lead[:4], rpmLeadMagic) {
+		return nil, errors.New("not a rpm file")
+	}
+
+	// the signature header is padded to 8 bytes, then the main header follows
+	_, size, err := readRPMHeader(file, rpmLeadSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature header: %s", err)
+	}
+	offset := int64(rpmLeadSize) + size
+	offset += (8 - offset%8) % 8
+	h, _, err := readRPMHeader(file, offset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %s", err)
+	}
+
+	d := &Data{
+		Format: "rpm",
+		Name:   h.string(rpmTagName),
+		Arch:   h.string(rpmTagArch),
+	}
+	d.Version = h.string(rpmTagVersion)
+	if release := h.string(rpmTagRelease); release != "" {
+		d.Version += "-" + release
+	}
+	if epoch, ok := h.int32(rpmTagEpoch); ok {
+		d.Version = strconv.FormatUint(uint64(epoch), 10) + ":" + d.Version
+	}
+
+	names := h.strings(rpmTagRequireName)
+	versions := h.strings(rpmTagRequireVersion)
+	flags := h.int32s(rpmTagRequireFlags)
+	for i, name := range names {
+		// rpmlib's features are not actual dependencies
+		if strings.HasPrefix(name, "rpmlib(") {
+			continue
+		}
+		dep := name
+		if i < len(versions) && versions[i] != "" && i < len(flags) {
+			dep += " " + rpmSense(flags[i]) + " " + versions[i]
+		}
+		d.Dependencies = append(d.Dependencies, dep)
+	}
+	return d, nil
+}
+
+// readRPMHeader reads the header structure at offset, returning it along with its total size.
+func readRPMHeader(r io.ReaderAt, offset int64) (*rpmHeader, int64, error) {
+	intro := make([]byte, 16)
+	if _, err := r.ReadAt(intro, offset); err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(intro[:4], rpmHeaderMagic) {
+		return nil, 0, errors.New("bad magic")
+	}
+	nindex := binary.BigEndian.Uint32(intro[8:12])
+	hsize := binary.BigEndian.Uint32(intro[12:16])
+	if nindex > 0xffff || hsize > 256<<20 {
+		return nil, 0, errors.New("header too large")
+	}
+
+	data := make([]byte, int(nindex)*16+int(hsize))
+	if _, err := r.ReadAt(data, offset+16); err != nil {
+		return nil, 0, err
+	}
+	h := &rpmHeader{
+		entries: make(map[uint32]rpmEntry, nindex),
+		store:   data[nindex*16:],
+	}
+	for i := uint32(0); i < nindex; i++ {
+		e := data[i*16 : i*16+16]
+		h.entries[binary.BigEndian.Uint32(e[0:4])] = rpmEntry{
+			typ:    binary.BigEndian.Uint32(e[4:8]),
+			offset: binary.BigEndian.Uint32(e[8:12]),
+			count:  binary.BigEndian.Uint32(e[12:16]),
+		}
+	}
+	return h, int64(16 + len(data)), nil
+}
+
+// strings returns the strings of the given tag.
+func (h *rpmHeader) strings(tag uint32) []string {
+	e, ok := h.entries[tag]
+	if !ok || int(e.offset) >= len(h.store) {
+		return nil
+	}
+	switch e.typ {
+	case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+	default:
+		return nil
+	}
+	values := []string{}
+	data := h.store[e.offset:]
+	for i := uint32(0); i < e.count; i++ {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			break
+		}
+		values = append(values, string(data[:end]))
+		data = data[end+1:]
+	}
+	return values
+}
+
+// string returns the (first) string of the given tag.
+func (h *rpmHeader) string(tag uint32) string {
+	if values := h.strings(tag); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// int32s returns the integers of the given tag.
+func (h *rpmHeader) int32s(tag uint32) []uint32 {
+	e, ok := h.entries[tag]
+	if !ok || e.typ != rpmTypeInt32 || uint64(e.offset)+uint64(e.count)*4 > uint64(len(h.store)) {
+		return nil
+	}
+	values := make([]uint32, e.count)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint32(h.store[int(e.offset)+i*4:])
+	}
+	return values
+}
+
+// int32 returns the (first) integer of the given tag.
+func (h *rpmHeader) int32(tag uint32) (uint32, bool) {
+	if values := h.int32s(tag); len(values) > 0 {
+		return values[0], true
+	}
+	return 0, false
+}
+
+// rpmSense returns the comparison operator of the given dependency flags.
+func rpmSense(flags uint32) string {
+	op := ""
+	if flags&rpmSenseLess != 0 {
+		op += "<"
+	}
+	if flags&rpmSenseGreater != 0 {
+		op += ">"
+	}
+	if flags&rpmSenseEqual != 0 {
+		op += "="
+	}
+	return op
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package pkginfo
+
+import (
+	"archive/zip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// Wheel returns the info declared by the core metadata of a Python wheel.
+// The architecture is the platform tag of the wheel.
+func Wheel(file *os.File) (*Data, error) {
+	zr, err := openZip(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata, wheel []field
+	for _, f := range zr.File {
+		dir, name := path.Split(f.Name)
+		// only the top level .dist-info directory is considered
+		if !strings.HasSuffix(dir, ".dist-info/") || strings.Count(dir, "/") != 1 {
+			continue
+		}
+		switch name {
+		case "METADATA":
+			data, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			metadata = parseFields(data, "\n")
+		case "WHEEL":
+			data, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			wheel = parseFields(data, "\n")
+		}
+	}
+	if metadata == nil {
+		return nil, errors.New("METADATA not found")
+	}
+
+	d := &Data{
+		Format:       "wheel",
+		Name:         get(metadata, "Name"),
+		Version:      get(metadata, "Version"),
+		Dependencies: getAll(metadata, "Requires-Dist"),
+	}
+	if len(d.Dependencies) == 0 {
+		d.Dependencies = nil
+	}
+	// tags are formatted as {python tag}-{abi tag}-{platform tag}
+	if tag := get(wheel, "Tag"); tag != "" {
+		d.Arch = tag[strings.LastIndex(tag, "-")+1:]
+	}
+	return d, nil
+}
+
+func openZip(file *os.File) (*zip.Reader, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(file, stat.Size())
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
@@ -10,10 +10,15 @@ package sniff
 
 import (
 	"debug/elf"
+	"encoding/binary"
+	"encoding/hex"
 	"os"
 	"strings"
 )
 
+// elfNoteGNUBuildID is the type of the GNU build-id note (NT_GNU_BUILD_ID)
+const elfNoteGNUBuildID = 3
+
 var elfosabiDesc = map[elf.OSABI]string{
 	elf.ELFOSABI_HPUX:       "HP-UX operating system",
 	elf.ELFOSABI_NETBSD:     "NetBSD",
@@ -58,6 +63,51 @@ func ELF(file *os.File) (*Data, error) {
 		Platform: platform,
 		Arch:     strings.TrimPrefix(f.Machine.String(), "EM_"),
 		X64:      f.Class == elf.ELFCLASS64,
+		BuildID:  elfBuildID(f),
+	}
+	// static executables have no dynamic section
+	if libs, err := f.ImportedLibraries(); err == nil && len(libs) > 0 {
+		d.Libraries = libs
 	}
 	return d, nil
 }
+
+// elfBuildID returns the hex encoded GNU build-id of f, if any.
+func elfBuildID(f *elf.File) string {
+	for _, s := range f.Sections {
+		if s.Type != elf.SHT_NOTE {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			continue
+		}
+		if id := gnuBuildIDNote(data, f.ByteOrder); id != nil {
+			return hex.EncodeToString(id)
+		}
+	}
+	return ""
+}
+
+// gnuBuildIDNote returns the descriptor of the GNU build-id note within the given notes, if any.
+// Each note is made of namesz, descsz and type (4 bytes each), followed by name and desc, both 4-byte aligned.
+func gnuBuildIDNote(data []byte, order binary.ByteOrder) []byte {
+	// sizes are aligned as uint64, so that crafted sizes cannot wrap around
+	align := func(n uint32) uint64 { return (uint64(n) + 3) &^ 3 }
+	for len(data) >= 12 {
+		namesz := order.Uint32(data[0:4])
+		descsz := order.Uint32(data[4:8])
+		typ := order.Uint32(data[8:12])
+		data = data[12:]
+		if align(namesz)+align(descsz) > uint64(len(data)) {
+			return nil
+		}
+		name := strings.TrimRight(string(data[:namesz]), "\x00")
+		desc := data[align(namesz) : align(namesz)+uint64(descsz)]
+		if name == "GNU" && typ == elfNoteGNUBuildID {
+			return desc
+		}
+		data = data[align(namesz)+align(descsz):]
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sniff
+
+// GoBuildInfo is the build info embedded by the Go toolchain into Go binaries.
+type GoBuildInfo struct {
+	GoVersion string            `json:"goVersion"`
+	Path      string            `json:"path,omitempty"`
+	Main      *GoModule         `json:"main,omitempty"`
+	Deps      []GoModule        `json:"deps,omitempty"`
+	Settings  map[string]string `json:"settings,omitempty"`
+}
+
+// GoModule is a module that went into a Go binary.
+type GoModule struct {
+	Path    string    `json:"path"`
+	Version string    `json:"version,omitempty"`
+	Sum     string    `json:"sum,omitempty"`
+	Replace *GoModule `json:"replace,omitempty"`
+}
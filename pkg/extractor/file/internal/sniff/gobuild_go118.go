@@ -0,0 +1,56 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sniff
+
+import (
+	"debug/buildinfo"
+	"os"
+	"runtime/debug"
+)
+
+// GoBuild returns the build info embedded into file, if it is a Go binary.
+func GoBuild(file *os.File) (*GoBuildInfo, error) {
+	bi, err := buildinfo.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &GoBuildInfo{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Path,
+		Main:      goModule(&bi.Main),
+	}
+	for _, dep := range bi.Deps {
+		if m := goModule(dep); m != nil {
+			info.Deps = append(info.Deps, *m)
+		}
+	}
+	if len(bi.Settings) > 0 {
+		info.Settings = make(map[string]string, len(bi.Settings))
+		for _, s := range bi.Settings {
+			info.Settings[s.Key] = s.Value
+		}
+	}
+	return info, nil
+}
+
+func goModule(m *debug.Module) *GoModule {
+	if m == nil || m.Path == "" {
+		return nil
+	}
+	return &GoModule{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+		Replace: goModule(m.Replace),
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sniff
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoBuild(t *testing.T) {
+	// the test binary itself is a Go binary
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	d, err := File(f)
+	assert.NoError(t, err)
+	if assert.NotNil(t, d.Go) {
+		assert.Equal(t, runtime.Version(), d.Go.GoVersion)
+		assert.Equal(t, runtime.GOOS, d.Go.Settings["GOOS"])
+		assert.Equal(t, runtime.GOARCH, d.Go.Settings["GOARCH"])
+	}
+
+	// not a Go binary
+	tmp, err := ioutil.TempFile("", "vcn-test-sniff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	tmp.WriteString("not a binary")
+
+	info, err := GoBuild(tmp)
+	assert.Error(t, err)
+	assert.Nil(t, info)
+}
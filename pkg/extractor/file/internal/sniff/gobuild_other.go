@@ -0,0 +1,23 @@
+//go:build !go1.18
+// +build !go1.18
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sniff
+
+import (
+	"errors"
+	"os"
+)
+
+// GoBuild returns the build info embedded into file, if it is a Go binary.
+// Reading the build info requires Go 1.18 or later, so it is not available with this build.
+func GoBuild(file *os.File) (*GoBuildInfo, error) {
+	return nil, errors.New("go build info is not supported")
+}
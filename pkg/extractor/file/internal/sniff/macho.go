@@ -10,13 +10,22 @@ package sniff
 
 import (
 	"debug/macho"
+	"fmt"
 	"os"
 	"strings"
 )
 
 const Platform_MachO = "Mach"
 
+// machoLoadCmdUUID is the LC_UUID load command
+const machoLoadCmdUUID macho.LoadCmd = 0x1b
+
 func MachO(file *os.File) (*Data, error) {
+	// universal binaries embed a Mach-O file for each architecture
+	if ff, err := macho.NewFatFile(file); err == nil {
+		return machOFat(ff)
+	}
+
 	f, err := macho.NewFile(file)
 	if err != nil {
 		return nil, err
@@ -25,10 +34,51 @@ func MachO(file *os.File) (*Data, error) {
 	cpu := strings.TrimPrefix(f.Cpu.String(), "Cpu")
 
 	d := &Data{
+		Format:   "Mach-O",
 		Type:     f.Type.String(),
 		Platform: Platform_MachO,
 		Arch:     cpu,
 		X64:      strings.HasSuffix(cpu, "64"),
+		UUID:     machOUUID(f),
 	}
 	return d, nil
 }
+
+func machOFat(ff *macho.FatFile) (*Data, error) {
+	if len(ff.Arches) == 0 {
+		return nil, fmt.Errorf("universal binary has no architectures")
+	}
+
+	d := &Data{
+		Format:   "Mach-O universal",
+		Type:     ff.Arches[0].Type.String(),
+		Platform: Platform_MachO,
+	}
+	cpus := make([]string, len(ff.Arches))
+	for i, a := range ff.Arches {
+		cpus[i] = strings.TrimPrefix(a.Cpu.String(), "Cpu")
+		arch := Arch{
+			Type: a.Type.String(),
+			Arch: cpus[i],
+			X64:  strings.HasSuffix(cpus[i], "64"),
+			UUID: machOUUID(a.File),
+		}
+		d.X64 = d.X64 || arch.X64
+		d.Archs = append(d.Archs, arch)
+	}
+	d.Arch = strings.Join(cpus, ",")
+	return d, nil
+}
+
+// machOUUID returns the UUID of f, as given by its LC_UUID load command, if any.
+func machOUUID(f *macho.File) string {
+	for _, l := range f.Loads {
+		raw := l.Raw()
+		if len(raw) < 24 || macho.LoadCmd(f.ByteOrder.Uint32(raw[0:4])) != machoLoadCmdUUID {
+			continue
+		}
+		u := raw[8:24]
+		return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]))
+	}
+	return ""
+}
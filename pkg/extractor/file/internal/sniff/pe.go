@@ -10,7 +10,9 @@ package sniff
 
 import (
 	"debug/pe"
+	"encoding/binary"
 	"os"
+	"unicode/utf16"
 )
 
 const Platform_PE = "Windows"
@@ -40,6 +42,13 @@ var machineTypes = map[uint16]string{
 	pe.IMAGE_FILE_MACHINE_WCEMIPSV2: "WCEMIPSV2",
 }
 
+const (
+	// peDirectoryEntrySecurity is the index of the certificate table within the data directories
+	peDirectoryEntrySecurity = 4
+	// peResourceTypeVersion is the RT_VERSION resource type
+	peResourceTypeVersion = 16
+)
+
 func PE(file *os.File) (*Data, error) {
 	f, err := pe.NewFile(file)
 	if err != nil {
@@ -49,9 +58,17 @@ func PE(file *os.File) (*Data, error) {
 	arch := machineTypes[f.FileHeader.Machine]
 
 	x64 := false
-	switch f.OptionalHeader.(type) {
+	var dirs []pe.DataDirectory
+	var numDirs uint32
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs, numDirs = oh.DataDirectory[:], oh.NumberOfRvaAndSizes
 	case *pe.OptionalHeader64:
 		x64 = true
+		dirs, numDirs = oh.DataDirectory[:], oh.NumberOfRvaAndSizes
+	}
+	if int(numDirs) < len(dirs) {
+		dirs = dirs[:numDirs]
 	}
 
 	format := "PE32"
@@ -66,5 +83,168 @@ func PE(file *os.File) (*Data, error) {
 		X64:      x64,
 		// Timestamp: f.TimeDateStamp,
 	}
+
+	// the Authenticode signature is stored within the certificate table (the security directory)
+	if len(dirs) > peDirectoryEntrySecurity && dirs[peDirectoryEntrySecurity].Size > 0 {
+		d.Signed = true
+	}
+
+	// version info is optional
+	if s := f.Section(".rsrc"); s != nil {
+		if data, err := s.Data(); err == nil {
+			if info := peVersionInfo(data, s.VirtualAddress); len(info) > 0 {
+				d.VersionInfo = info
+			}
+		}
+	}
 	return d, nil
 }
+
+// peVersionInfo returns the strings of the first version resource (VS_VERSIONINFO) found within the
+// resource section data, whose virtual address is va.
+func peVersionInfo(rsrc []byte, va uint32) map[string]string {
+	data := peResource(rsrc, va, peResourceTypeVersion)
+	if data == nil {
+		return nil
+	}
+	key, _, children, ok := peVersionBlock(data)
+	if !ok || key != "VS_VERSION_INFO" {
+		return nil
+	}
+	info := map[string]string{}
+	// children are StringFileInfo and VarFileInfo blocks
+	forEachPEVersionBlock(children, func(key string, _ []byte, tables []byte) {
+		if key != "StringFileInfo" {
+			return
+		}
+		// each table holds the strings for a language and code page, e.g. 040904b0
+		forEachPEVersionBlock(tables, func(_ string, _ []byte, entries []byte) {
+			forEachPEVersionBlock(entries, func(key string, value []byte, _ []byte) {
+				if _, ok := info[key]; !ok {
+					info[key] = utf16String(value)
+				}
+			})
+		})
+	})
+	return info
+}
+
+// peResource returns the data of the first resource of the given type, walking the resource directory tree
+// (type, name, language) found at the beginning of the resource section.
+func peResource(rsrc []byte, va uint32, typ uint32) []byte {
+	offset, ok := peResourceEntry(rsrc, 0, &typ)
+	for level := 0; ok && level < 2; level++ {
+		// name and language levels, the first entry is used
+		offset, ok = peResourceEntry(rsrc, offset, nil)
+	}
+	if !ok || offset&0x80000000 != 0 || int(offset)+16 > len(rsrc) {
+		return nil
+	}
+	// IMAGE_RESOURCE_DATA_ENTRY
+	rva := binary.LittleEndian.Uint32(rsrc[offset : offset+4])
+	size := binary.LittleEndian.Uint32(rsrc[offset+4 : offset+8])
+	if rva < va || uint64(rva-va)+uint64(size) > uint64(len(rsrc)) {
+		return nil
+	}
+	return rsrc[rva-va : rva-va+size]
+}
+
+// peResourceEntry returns the offset pointed by the entry of the resource directory at dir matching id,
+// or the first entry if id is nil. Offsets of subdirectories have the high bit set.
+func peResourceEntry(rsrc []byte, dir uint32, id *uint32) (uint32, bool) {
+	dir &= 0x7fffffff
+	if int(dir)+16 > len(rsrc) {
+		return 0, false
+	}
+	named := binary.LittleEndian.Uint16(rsrc[dir+12 : dir+14])
+	ids := binary.LittleEndian.Uint16(rsrc[dir+14 : dir+16])
+	for i := 0; i < int(named)+int(ids); i++ {
+		e := int(dir) + 16 + i*8
+		if e+8 > len(rsrc) {
+			return 0, false
+		}
+		name := binary.LittleEndian.Uint32(rsrc[e : e+4])
+		if id == nil || (name&0x80000000 == 0 && name == *id) {
+			return binary.LittleEndian.Uint32(rsrc[e+4 : e+8]), true
+		}
+	}
+	return 0, false
+}
+
+// peVersionBlock parses a version info block (wLength, wValueLength, wType, szKey, padding, value, padding, children),
+// returning its key, value and children.
+func peVersionBlock(b []byte) (key string, value []byte, children []byte, ok bool) {
+	if len(b) < 6 {
+		return
+	}
+	length := int(binary.LittleEndian.Uint16(b[0:2]))
+	valueLength := int(binary.LittleEndian.Uint16(b[2:4]))
+	text := binary.LittleEndian.Uint16(b[4:6]) == 1
+	if length < 6 || length > len(b) {
+		return
+	}
+	b = b[:length]
+
+	// szKey is a null terminated UTF-16 string
+	i := 6
+	for ; i+1 < len(b); i += 2 {
+		if b[i] == 0 && b[i+1] == 0 {
+			break
+		}
+	}
+	if i+1 >= len(b) {
+		return
+	}
+	key = utf16String(b[6:i])
+	i = align4(i + 2)
+
+	// text values' length is expressed in words
+	if text {
+		valueLength *= 2
+	}
+	if i+valueLength > len(b) {
+		valueLength = len(b) - i
+	}
+	if valueLength > 0 {
+		value = b[i : i+valueLength]
+	}
+	i = align4(i + valueLength)
+	if i < len(b) {
+		children = b[i:]
+	}
+	return key, value, children, true
+}
+
+// forEachPEVersionBlock calls fn for each version info block within b.
+func forEachPEVersionBlock(b []byte, fn func(key string, value []byte, children []byte)) {
+	for len(b) >= 6 {
+		length := int(binary.LittleEndian.Uint16(b[0:2]))
+		key, value, children, ok := peVersionBlock(b)
+		if !ok {
+			return
+		}
+		fn(key, value, children)
+		next := align4(length)
+		if next >= len(b) {
+			return
+		}
+		b = b[next:]
+	}
+}
+
+func align4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// utf16String decodes a little endian UTF-16 string, stopping at the first null character, if any.
+func utf16String(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i : i+2])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
@@ -19,6 +19,30 @@ type Data struct {
 	Platform string `json:"platform"`
 	Arch     string `json:"arch"`
 	X64      bool   `json:"x64"`
+
+	// BuildID is the GNU build-id (ELF only)
+	BuildID string `json:"buildId,omitempty"`
+	// Libraries are the needed shared libraries (ELF only)
+	Libraries []string `json:"libraries,omitempty"`
+	// VersionInfo holds the version resource's strings, e.g. ProductVersion and CompanyName (PE only)
+	VersionInfo map[string]string `json:"versionInfo,omitempty"`
+	// Signed reports whether an Authenticode signature is present, not whether it is valid (PE only)
+	Signed bool `json:"signed,omitempty"`
+	// UUID is the LC_UUID of the binary (Mach-O only, for universal binaries see Archs)
+	UUID string `json:"uuid,omitempty"`
+	// Archs are the architectures contained by a universal binary (Mach-O only)
+	Archs []Arch `json:"archs,omitempty"`
+
+	// Go holds the embedded build info for Go binaries only
+	Go *GoBuildInfo `json:"go,omitempty"`
+}
+
+// Arch is a single architecture of a universal binary.
+type Arch struct {
+	Type string `json:"type"`
+	Arch string `json:"arch"`
+	X64  bool   `json:"x64"`
+	UUID string `json:"uuid,omitempty"`
 }
 
 func (d Data) ContentType() string {
@@ -43,6 +67,8 @@ func File(file *os.File) (*Data, error) {
 
 	for _, sniffer := range sniffers {
 		if d, e := sniffer(file); e == nil {
+			// build info is optional, since not all executables are Go binaries
+			d.Go, _ = GoBuild(file)
 			return d, nil
 		}
 	}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package sniff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempFile(t *testing.T, data []byte) *os.File {
+	f, err := ioutil.TempFile("", "vcn-test-sniff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestGNUBuildIDNote(t *testing.T) {
+	note := func(name string, typ uint32, desc []byte) []byte {
+		b := &bytes.Buffer{}
+		n := append([]byte(name), 0)
+		binary.Write(b, binary.LittleEndian, []uint32{uint32(len(n)), uint32(len(desc)), typ})
+		b.Write(n)
+		b.Write(make([]byte, align4(len(n))-len(n)))
+		b.Write(desc)
+		b.Write(make([]byte, align4(len(desc))-len(desc)))
+		return b.Bytes()
+	}
+	id := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	data := append(note("Go", 4, []byte("go-build-id")), note("GNU", elfNoteGNUBuildID, id)...)
+
+	assert.Equal(t, id, gnuBuildIDNote(data, binary.LittleEndian))
+	assert.Nil(t, gnuBuildIDNote(note("Go", 4, []byte("go-build-id")), binary.LittleEndian))
+	assert.Nil(t, gnuBuildIDNote(data[:20], binary.LittleEndian))
+
+	// malformed sizes, whose alignment would wrap around as uint32
+	for _, sizes := range [][2]uint32{{0xfffffffd, 0}, {0xffffffff, 0xffffffff}, {4, 0xfffffffe}} {
+		b := &bytes.Buffer{}
+		binary.Write(b, binary.LittleEndian, []uint32{sizes[0], sizes[1], elfNoteGNUBuildID})
+		b.WriteString("GNU\x00")
+		assert.NotPanics(t, func() {
+			assert.Nil(t, gnuBuildIDNote(b.Bytes(), binary.LittleEndian))
+		})
+	}
+}
+
+func versionBlock(key string, value []byte, text bool, children ...[]byte) []byte {
+	b := &bytes.Buffer{}
+	b.Write(make([]byte, 6))
+	for _, c := range utf16.Encode([]rune(key + "\x00")) {
+		binary.Write(b, binary.LittleEndian, c)
+	}
+	b.Write(make([]byte, align4(b.Len())-b.Len()))
+	b.Write(value)
+	for _, c := range children {
+		b.Write(make([]byte, align4(b.Len())-b.Len()))
+		b.Write(c)
+	}
+	data := b.Bytes()
+	valueLength := len(value)
+	if text {
+		valueLength /= 2
+	}
+	binary.LittleEndian.PutUint16(data[0:2], uint16(len(data)))
+	binary.LittleEndian.PutUint16(data[2:4], uint16(valueLength))
+	if text {
+		binary.LittleEndian.PutUint16(data[4:6], 1)
+	}
+	return data
+}
+
+func versionString(key, value string) []byte {
+	b := &bytes.Buffer{}
+	for _, c := range utf16.Encode([]rune(value + "\x00")) {
+		binary.Write(b, binary.LittleEndian, c)
+	}
+	return versionBlock(key, b.Bytes(), true)
+}
+
+func TestPEVersionInfo(t *testing.T) {
+	info := versionBlock("VS_VERSION_INFO", make([]byte, 52), false,
+		versionBlock("StringFileInfo", nil, true,
+			versionBlock("040904b0", nil, true,
+				versionString("CompanyName", "vChain, Inc."),
+				versionString("ProductVersion", "0.9.0"),
+			),
+		),
+		versionBlock("VarFileInfo", nil, true,
+			versionBlock("Translation", []byte{0x09, 0x04, 0xb0, 0x04}, false),
+		),
+	)
+
+	// resource directory tree: type (RT_VERSION), name and language, then the data entry
+	const va = 0x3000
+	rsrc := make([]byte, 88)
+	dir := func(offset int, id uint32, target uint32) {
+		binary.LittleEndian.PutUint16(rsrc[offset+14:], 1)
+		binary.LittleEndian.PutUint32(rsrc[offset+16:], id)
+		binary.LittleEndian.PutUint32(rsrc[offset+20:], target)
+	}
+	dir(0, peResourceTypeVersion, 0x80000000|24)
+	dir(24, 1, 0x80000000|48)
+	dir(48, 0x409, 72)
+	binary.LittleEndian.PutUint32(rsrc[72:], va+88)
+	binary.LittleEndian.PutUint32(rsrc[76:], uint32(len(info)))
+	rsrc = append(rsrc, info...)
+
+	assert.Equal(t, map[string]string{
+		"CompanyName":    "vChain, Inc.",
+		"ProductVersion": "0.9.0",
+	}, peVersionInfo(rsrc, va))
+
+	// no version resource
+	binary.LittleEndian.PutUint32(rsrc[16:], 3)
+	assert.Nil(t, peVersionInfo(rsrc, va))
+}
+
+func machOFile(cpu uint32, uuid []byte) []byte {
+	b := &bytes.Buffer{}
+	// mach_header_64: magic, cputype, cpusubtype, filetype (MH_EXECUTE), ncmds, sizeofcmds, flags, reserved
+	binary.Write(b, binary.LittleEndian, []uint32{0xfeedfacf, cpu, 3, 2, 1, 24, 0, 0})
+	// uuid_command
+	binary.Write(b, binary.LittleEndian, []uint32{uint32(machoLoadCmdUUID), 24})
+	b.Write(uuid)
+	return b.Bytes()
+}
+
+func TestMachO(t *testing.T) {
+	uuid1 := []byte{0x0a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7, 0xe8, 0xf9}
+	uuid2 := make([]byte, 16)
+	uuid2[15] = 1
+
+	f := tempFile(t, machOFile(0x01000007, uuid1))
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	d, err := MachO(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mach-O", d.Format)
+	assert.Equal(t, "Amd64", d.Arch)
+	assert.True(t, d.X64)
+	assert.Equal(t, "0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9", d.UUID)
+	assert.Empty(t, d.Archs)
+
+	// universal binary
+	amd64 := machOFile(0x01000007, uuid1)
+	arm64 := machOFile(0x0100000c, uuid2)
+	fat := &bytes.Buffer{}
+	binary.Write(fat, binary.BigEndian, []uint32{0xcafebabe, 2})
+	binary.Write(fat, binary.BigEndian, []uint32{0x01000007, 3, 0x1000, uint32(len(amd64)), 12})
+	binary.Write(fat, binary.BigEndian, []uint32{0x0100000c, 0, 0x2000, uint32(len(arm64)), 12})
+	data := make([]byte, 0x2000+len(arm64))
+	copy(data, fat.Bytes())
+	copy(data[0x1000:], amd64)
+	copy(data[0x2000:], arm64)
+
+	ff := tempFile(t, data)
+	defer os.Remove(ff.Name())
+	defer ff.Close()
+
+	d, err = File(ff)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mach-O universal", d.Format)
+	assert.Equal(t, "Amd64,Arm64", d.Arch)
+	assert.Equal(t, "application/x-mach-binary", d.ContentType())
+	assert.Equal(t, []Arch{
+		{Type: "Exec", Arch: "Amd64", X64: true, UUID: "0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9"},
+		{Type: "Exec", Arch: "Arm64", X64: true, UUID: "00000000-0000-0000-0000-000000000001"},
+	}, d.Archs)
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package file
+
+import (
+	"os"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor/file/internal/pkginfo"
+)
+
+// PackageKey is the metadata's key for storing the info declared by package files
+const PackageKey = "package"
+
+// pkgInfo returns the metadata and the name declared by file, if it is a supported package file
+// (deb, rpm, Python wheel, Java archive or npm tarball).
+func pkgInfo(file *os.File) (bool, api.Metadata, string) {
+	d, err := pkginfo.File(file)
+	if err != nil {
+		return false, nil, ""
+	}
+	m := api.Metadata{
+		PackageKey: d,
+	}
+	// the declared version overrides the one inferred from the filename
+	if d.Version != "" {
+		m["version"] = d.Version
+	}
+	if d.Arch != "" {
+		m["architecture"] = d.Arch
+	}
+	return true, m, d.Name
+}
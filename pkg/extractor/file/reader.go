@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package file
+
+import (
+	"io"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor"
+)
+
+// ReaderArtifact returns a file *api.Artifact for the content read from r (until EOF), named as name.
+//
+// The content is hashed while streaming, so it is never stored on disk and the resulting hash matches
+// the one of a file with the same content. Only the content type is sniffed (by using the first 512 bytes),
+// while executable info cannot be extracted since it requires random access to the content.
+func ReaderArtifact(r io.Reader, name string, options ...extractor.Option) ([]*api.Artifact, error) {
+	opts := &opts{}
+	if err := extractor.Options(options).Apply(opts); err != nil {
+		return nil, err
+	}
+
+	d, err := newDigester(opts.digests)
+	if err != nil {
+		return nil, err
+	}
+
+	// Metadata container
+	m := api.Metadata{}
+
+	// ContentType, sniffed by the head of the content
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	ct := ""
+	if n > 0 {
+		ct = sniffContentType(head)
+	}
+
+	// Hash and Size
+	d.Write(head[:n])
+	size, err := io.Copy(d, r)
+	if err != nil {
+		return nil, err
+	}
+	size += int64(n)
+	if digests := d.Extra(); digests != nil {
+		m[DigestsKey] = digests
+	}
+
+	// Infer version from name
+	if version := inferVer(name); version != "" {
+		m["version"] = version
+	}
+
+	return []*api.Artifact{{
+		Kind:        Scheme,
+		Name:        name,
+		Hash:        d.Primary(),
+		Size:        uint64(size),
+		ContentType: ct,
+		Metadata:    m,
+	}}, nil
+}
@@ -13,20 +13,10 @@ import (
 	"encoding/hex"
 	"io"
 
-	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
-func lastCommit(repo *git.Repository) (*object.Commit, error) {
-	ref, err := repo.Head()
-	if err != nil {
-		return nil, err
-	}
-
-	return repo.CommitObject(ref.Hash())
-}
-
 func digestCommit(c object.Commit) (hash string, size uint64, err error) {
 	o := &plumbing.MemoryObject{}
 	c.Encode(o)
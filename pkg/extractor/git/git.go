@@ -9,10 +9,14 @@
 package git
 
 import (
+	"fmt"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 
 	"github.com/vchain-us/vcn/pkg/api"
 	"github.com/vchain-us/vcn/pkg/extractor"
@@ -22,6 +26,21 @@ import (
 // Scheme for git
 const Scheme = "git"
 
+func init() {
+	// options can be given by the URI's query
+	uri.RegisterQueryScheme(Scheme)
+}
+
+// ManifestKey is the metadata's key for storing the tree manifest
+const ManifestKey = "manifest"
+
+// PathKey is the metadata's key for the repository path
+const PathKey = "path"
+
+type opts struct {
+	treeContent bool
+}
+
 // Artifact returns a git *api.Artifact from a given u
 func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
 
@@ -29,22 +48,94 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		return nil, nil
 	}
 
-	path := strings.TrimPrefix(u.Opaque, "//")
-	path, err := filepath.Abs(path)
+	opts := &opts{}
+	if err := extractor.Options(options).Apply(opts); err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	if err := opts.applyQuery(q); err != nil {
+		return nil, err
+	}
+
+	t, err := parseTarget(strings.TrimPrefix(u.Opaque, "//"))
 	if err != nil {
 		return nil, err
 	}
+	if ref := q.Get("ref"); ref != "" {
+		if t.ref != "" || t.from != "" {
+			return nil, fmt.Errorf("cannot use the ref query parameter with a ref or a range")
+		}
+		t.ref = ref
+	}
 
-	repo, err := git.PlainOpen(path)
+	path, err := filepath.Abs(t.path)
 	if err != nil {
 		return nil, err
 	}
 
-	commit, err := lastCommit(repo)
+	repo, err := git.PlainOpen(path)
 	if err != nil {
 		return nil, err
 	}
 
+	var commits []*object.Commit
+	if t.from != "" {
+		commits, err = commitRange(repo, t.from, t.to)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		commit, err := resolveCommit(repo, t.ref)
+		if err != nil {
+			return nil, err
+		}
+		commits = []*object.Commit{commit}
+	}
+
+	name := filepath.Base(path)
+	if remotes, err := repo.Remotes(); err == nil && len(remotes) > 0 {
+		urls := remotes[0].Config().URLs
+		if len(urls) > 0 {
+			name = urls[0]
+		}
+	}
+
+	// when a tag is referenced, it's used as version
+	var version string
+	if t.ref != "" {
+		if _, err := repo.Tag(t.ref); err == nil {
+			version = t.ref
+		}
+	}
+
+	artifacts := make([]*api.Artifact, len(commits))
+	for i, commit := range commits {
+		artifacts[i], err = commitArtifact(name, commit, version)
+		if err != nil {
+			return nil, err
+		}
+		if opts.treeContent {
+			if err := digestTreeContent(artifacts[i], commit, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return artifacts, nil
+}
+
+// applyQuery sets the options given by the URI's query (i.e. "tree=<bool>"), overriding the functional ones.
+func (o *opts) applyQuery(q url.Values) error {
+	if v := q.Get("tree"); v != "" {
+		tree, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid tree query parameter: %s", v)
+		}
+		o.treeContent = tree
+	}
+	return nil
+}
+
+func commitArtifact(name string, commit *object.Commit, version string) (*api.Artifact, error) {
 	hash, size, err := digestCommit(*commit)
 	if err != nil {
 		return nil, err
@@ -68,21 +159,56 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 			"PGPSignature": commit.PGPSignature,
 		},
 	}
-
-	name := filepath.Base(path)
-	if remotes, err := repo.Remotes(); err == nil && len(remotes) > 0 {
-		urls := remotes[0].Config().URLs
-		if len(urls) > 0 {
-			name = urls[0]
-		}
+	if version != "" {
+		m["version"] = version
 	}
-	name += "@" + commit.Hash.String()[:7]
 
-	return []*api.Artifact{{
+	return &api.Artifact{
 		Kind:     Scheme,
 		Hash:     hash,
 		Size:     size,
-		Name:     name,
+		Name:     name + "@" + commit.Hash.String()[:7],
 		Metadata: m,
-	}}, nil
+	}, nil
+}
+
+// digestTreeContent replaces a's hash and size with the ones of the commit's tree content,
+// and stores the tree manifest into a's metadata.
+func digestTreeContent(a *api.Artifact, commit *object.Commit, path string) error {
+	manifest, err := treeManifest(commit)
+	if err != nil {
+		return err
+	}
+	digest, err := manifest.Digest()
+	if err != nil {
+		return err
+	}
+
+	var size uint64
+	for _, d := range manifest.Items {
+		size += d.Size * uint64(len(d.Paths))
+	}
+
+	a.Hash = digest.Encoded()
+	a.Size = size
+	a.Metadata[ManifestKey] = manifest
+	a.Metadata[PathKey] = path
+	return nil
+}
+
+// WithTreeContent returns a functional option to instruct the git's extractor to hash the content of the
+// commit's tree (through a bundle.Manifest of its blobs) instead of the commit object.
+// Commits with identical trees will result in the same hash, that also matches dir:// with the same content.
+//
+// Since dir:// hashes any file of a checkout that is not ignored, the hash matches dir:// of the same checkout
+// only if the commit's tree holds a .vcnignore file excluding the .git directory (e.g. a ".git" line),
+// and there are no untracked files. So, when notarizing the checkout by dir://, such a .vcnignore must be
+// committed first, otherwise the default one created by `vcn notarize` is hashed as an untracked file.
+func WithTreeContent() extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.treeContent = true
+		}
+		return nil
+	}
 }
@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"github.com/vchain-us/vcn/pkg/uri"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// initTestRepo creates a repository with n commits and returns its path and the commits' hashes (oldest first).
+func initTestRepo(t *testing.T, n int) (string, []plumbing.Hash) {
+	path, err := ioutil.TempDir("", "vcn-test-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := make([]plumbing.Hash, n)
+	for i := 0; i < n; i++ {
+		content := []byte{byte('a' + i)}
+		if err := ioutil.WriteFile(filepath.Join(path, "file"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add("file"); err != nil {
+			t.Fatal(err)
+		}
+		hashes[i], err = wt.Commit("commit "+string(content), &git.CommitOptions{
+			Author: &object.Signature{Name: "vcn", Email: "vcn@example.com", When: time.Unix(int64(i), 0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, hashes
+}
+
+func TestArtifact(t *testing.T) {
+	path, hashes := initTestRepo(t, 3)
+	defer os.RemoveAll(path)
+
+	repo, _ := git.PlainOpen(path)
+	_, err := repo.CreateTag("v1.0", hashes[0], nil)
+	assert.NoError(t, err)
+
+	commitHash := func(rawURI string) []string {
+		u, _ := uri.Parse(rawURI)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		res := make([]string, len(artifacts))
+		for i, a := range artifacts {
+			res[i] = a.Metadata[Scheme].(map[string]interface{})["Commit"].(string)
+		}
+		return res
+	}
+
+	// HEAD
+	assert.Equal(t, []string{hashes[2].String()}, commitHash("git://"+path))
+
+	// by branch, tag, short and full SHA
+	assert.Equal(t, []string{hashes[2].String()}, commitHash("git://"+path+"@master"))
+	assert.Equal(t, []string{hashes[0].String()}, commitHash("git://"+path+"@v1.0"))
+	assert.Equal(t, []string{hashes[1].String()}, commitHash("git://"+path+"@"+hashes[1].String()[:7]))
+	assert.Equal(t, []string{hashes[1].String()}, commitHash("git://"+path+"@"+hashes[1].String()))
+
+	// tag as version
+	u, _ := uri.Parse("git://" + path + "@v1.0")
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0", artifacts[0].Metadata["version"])
+
+	// by range
+	assert.Equal(
+		t,
+		[]string{hashes[2].String(), hashes[1].String()},
+		commitHash("git://"+path+"#range=v1.0..master"),
+	)
+
+	// unknown ref - ERROR
+	u, _ = uri.Parse("git://" + path + "@not-existing")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// empty range - ERROR
+	u, _ = uri.Parse("git://" + path + "#range=master..v1.0")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// both ref and range - ERROR
+	u, _ = uri.Parse("git://" + path + "@master#range=v1.0..master")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// ref by query
+	assert.Equal(t, []string{hashes[0].String()}, commitHash("git://"+path+"?ref=v1.0"))
+
+	// both ref and ref query - ERROR
+	u, _ = uri.Parse("git://" + path + "@master?ref=v1.0")
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
+
+func TestArtifactWithTreeContent(t *testing.T) {
+	path, hashes := initTestRepo(t, 1)
+	defer os.RemoveAll(path)
+
+	// a second commit with the same tree
+	repo, _ := git.PlainOpen(path)
+	wt, _ := repo.Worktree()
+	_, err := wt.Commit("same tree", &git.CommitOptions{
+		Author: &object.Signature{Name: "vcn", Email: "vcn@example.com", When: time.Unix(10, 0)},
+	})
+	assert.NoError(t, err)
+
+	u, _ := uri.Parse("git://" + path + "@" + hashes[0].String())
+	first, err := Artifact(u, WithTreeContent())
+	assert.NoError(t, err)
+
+	u, _ = uri.Parse("git://" + path)
+	second, err := Artifact(u, WithTreeContent())
+	assert.NoError(t, err)
+
+	assert.Equal(t, first[0].Hash, second[0].Hash)
+	manifest, p := Metadata(*second[0])
+	assert.Len(t, manifest.Items, 1)
+	assert.Equal(t, path, p)
+
+	// same content as dir://
+	tmpDir, err := ioutil.TempDir("", "vcn-test-git-dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "file"), []byte("a"), 0644))
+	u, _ = uri.Parse("dir://" + tmpDir)
+	dirArtifacts, err := dir.Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, dirArtifacts[0].Hash, first[0].Hash)
+
+	// without tree content, commits differ
+	u, _ = uri.Parse("git://" + path)
+	withoutTree, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first[0].Hash, withoutTree[0].Hash)
+
+	// tree content by query, overriding options
+	u, _ = uri.Parse("git://" + path + "?tree=true")
+	byQuery, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, second[0].Hash, byQuery[0].Hash)
+	u, _ = uri.Parse("git://" + path + "?tree=false")
+	byQuery, err = Artifact(u, WithTreeContent())
+	assert.NoError(t, err)
+	assert.Equal(t, withoutTree[0].Hash, byQuery[0].Hash)
+}
+
+func TestArtifactWithTreeContentNestedIgnoreFiles(t *testing.T) {
+	path, _ := initTestRepo(t, 1)
+	defer os.RemoveAll(path)
+
+	repo, _ := git.PlainOpen(path)
+	wt, _ := repo.Worktree()
+	files := map[string]string{
+		dir.IgnoreFilename:          ".git\n*.log\n",
+		"a.log":                     "a",
+		"sub/" + dir.IgnoreFilename: "!keep.log\n",
+		"sub/keep.log":              "b",
+		"sub/other.log":             "c",
+	}
+	for name, content := range files {
+		p := filepath.Join(path, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := wt.Commit("ignore files", &git.CommitOptions{
+		Author: &object.Signature{Name: "vcn", Email: "vcn@example.com", When: time.Unix(10, 0)},
+	})
+	assert.NoError(t, err)
+
+	u, _ := uri.Parse("git://" + path)
+	artifacts, err := Artifact(u, WithTreeContent())
+	assert.NoError(t, err)
+	manifest, _ := Metadata(*artifacts[0])
+	assert.Len(t, manifest.Items, 4) // file, .vcnignore, sub/.vcnignore, sub/keep.log
+
+	// the checkout processed as dir:// matches
+	u, _ = uri.Parse("dir://" + path)
+	dirArtifacts, err := dir.Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, dirArtifacts[0].Hash, artifacts[0].Hash)
+}
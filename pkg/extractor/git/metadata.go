@@ -0,0 +1,25 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package git
+
+import (
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
+)
+
+// Metadata extracts tree content related info from a, if any.
+func Metadata(a api.Artifact) (manifest *bundle.Manifest, path string) {
+	return extractor.ManifestMetadata(a, Scheme, ManifestKey, PathKey)
+}
+
+// RemoveMetadata removes tree content related info from a.
+func RemoveMetadata(a *api.Artifact) {
+	extractor.RemoveManifestMetadata(a, Scheme, ManifestKey, PathKey)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const rangeFragmentPrefix = "range="
+
+var shortSHARegExp = regexp.MustCompile("^[0-9a-f]{4,40}$")
+
+// target is the parsed form of "<path>[@<ref>][#range=<from>..<to>]"
+type target struct {
+	path string
+	ref  string
+	from string
+	to   string
+}
+
+// parseTarget splits s into its components. The ref is split off at the first "@" preceded by an existing path,
+// so that paths containing "@" are still supported.
+func parseTarget(s string) (*target, error) {
+	t := &target{}
+	if i := strings.LastIndex(s, "#"); i >= 0 {
+		fragment := s[i+1:]
+		s = s[:i]
+		if !strings.HasPrefix(fragment, rangeFragmentPrefix) {
+			return nil, fmt.Errorf("unsupported fragment: %s", fragment)
+		}
+		parts := strings.SplitN(strings.TrimPrefix(fragment, rangeFragmentPrefix), "..", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid range, <from>..<to> expected: %s", fragment)
+		}
+		t.from, t.to = parts[0], parts[1]
+	}
+
+	t.path = s
+	if _, err := os.Stat(s); err != nil {
+		for i := 1; i < len(s); i++ {
+			if s[i] != '@' {
+				continue
+			}
+			if _, err := os.Stat(s[:i]); err == nil {
+				t.path, t.ref = s[:i], s[i+1:]
+				break
+			}
+		}
+	}
+
+	if t.ref != "" && t.from != "" {
+		return nil, fmt.Errorf("cannot use both a ref and a range")
+	}
+	return t, nil
+}
+
+func lastCommit(repo *git.Repository) (*object.Commit, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.CommitObject(ref.Hash())
+}
+
+// resolveCommit returns the commit referenced by rev (a branch, a tag, a short or full SHA).
+// If rev is empty, the HEAD commit is returned.
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	if rev == "" {
+		return lastCommit(repo)
+	}
+
+	if h, err := repo.ResolveRevision(plumbing.Revision(rev)); err == nil {
+		return repo.CommitObject(*h)
+	}
+
+	// try by short SHA
+	if shortSHARegExp.MatchString(rev) {
+		iter, err := repo.CommitObjects()
+		if err != nil {
+			return nil, err
+		}
+		var found *object.Commit
+		err = iter.ForEach(func(c *object.Commit) error {
+			if strings.HasPrefix(c.Hash.String(), rev) {
+				if found != nil {
+					return fmt.Errorf("short SHA %s is ambiguous", rev)
+				}
+				found = c
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown revision: %s", rev)
+}
+
+// commitRange returns the commits reachable from to, but not from from (as `git log <from>..<to>` does).
+// Commits are returned from the newest to the oldest.
+func commitRange(repo *git.Repository, from, to string) ([]*object.Commit, error) {
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[plumbing.Hash]bool)
+	iter, err := repo.Log(&git.LogOptions{From: fromCommit.Hash})
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		excluded[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*object.Commit, 0)
+	iter, err = repo.Log(&git.LogOptions{From: toCommit.Hash})
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		if excluded[c.Hash] {
+			return nil
+		}
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits found in range %s..%s", from, to)
+	}
+	return commits, nil
+}
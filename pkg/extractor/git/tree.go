@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package git
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor/dir"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// treeManifest returns a bundle.Manifest of the blobs within the c's tree.
+//
+// Blobs are processed the same way the dir package processes files,
+// so the resulting manifest matches the one of a dir:// with the same content:
+//  - only regular files are included (symlinks and submodules are skipped)
+//  - the manifest file and files matching the ignore files' patterns (at any depth) are skipped
+//
+// Note that a checkout's directory also holds the .git directory, that dir:// hashes unless it is ignored.
+func treeManifest(c *object.Commit) (*bundle.Manifest, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	ignore, err := treeIgnoreMatcher(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]bundle.Descriptor, 0)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable && f.Mode != filemode.Deprecated {
+			return nil
+		}
+		if f.Name == bundle.ManifestFilename {
+			return nil
+		}
+		if ignore.Match(strings.Split(f.Name, "/"), false) {
+			return nil
+		}
+
+		r, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		d, err := bundle.NewDescriptor(f.Name, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		files = append(files, *d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle.NewManifest(files...), nil
+}
+
+// treeIgnoreMatcher returns a gitignore.Matcher for all the ignore files within the tree,
+// each one scoped to its own directory. Patterns of deeper ignore files take precedence.
+func treeIgnoreMatcher(tree *object.Tree) (gitignore.Matcher, error) {
+	ignoreFiles := []*object.File{}
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if path.Base(f.Name) == dir.IgnoreFilename {
+			ignoreFiles = append(ignoreFiles, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ignoreFiles, func(i, j int) bool {
+		return strings.Count(ignoreFiles[i].Name, "/") < strings.Count(ignoreFiles[j].Name, "/")
+	})
+
+	ps := []gitignore.Pattern{}
+	for _, f := range ignoreFiles {
+		data, err := f.Contents()
+		if err != nil {
+			return nil, err
+		}
+		var domain []string
+		if d := path.Dir(f.Name); d != "." {
+			domain = strings.Split(d, "/")
+		}
+		ps = append(ps, dir.ParseIgnoreFile([]byte(data), domain)...)
+	}
+	return gitignore.NewMatcher(ps), nil
+}
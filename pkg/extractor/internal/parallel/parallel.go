@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+// Package parallel provides a bounded worker pool for extractors.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Jobs returns the number of workers to use for the given jobs value:
+// a value less than or equal to zero means one worker per CPU.
+func Jobs(jobs int) int {
+	if jobs <= 0 {
+		return runtime.NumCPU()
+	}
+	return jobs
+}
+
+// Do calls fn for each index in [0, n), running at most Jobs(jobs) calls concurrently.
+//
+// Indexes are dispatched in ascending order and no further index is dispatched once a call has failed.
+// The returned error is the one of the lowest failed index, that is the same error a sequential
+// loop would have returned.
+func Do(jobs, n int, fn func(i int) error) error {
+	jobs = Jobs(jobs)
+	if jobs > n {
+		jobs = n
+	}
+
+	errs := make([]error, n)
+	var failed int32
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if errs[i] = fn(i); errs[i] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n && atomic.LoadInt32(&failed) == 0; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package parallel
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	// all indexes are processed, within the bound
+	results := make([]int, 100)
+	var running, maxRunning int32
+	err := Do(4, len(results), func(i int) error {
+		r := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if r <= m || atomic.CompareAndSwapInt32(&maxRunning, m, r) {
+				break
+			}
+		}
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, maxRunning <= 4)
+	for i, r := range results {
+		assert.Equal(t, i*i, r)
+	}
+
+	// the error of the lowest failed index is returned
+	err = Do(8, 100, func(i int) error {
+		if i >= 10 && i%10 == 0 {
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "failed 10")
+
+	// nothing to do
+	assert.NoError(t, Do(0, 0, func(i int) error { return fmt.Errorf("unexpected") }))
+	assert.Equal(t, 3, Jobs(3))
+	assert.True(t, Jobs(0) > 0)
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package k8s
+
+import (
+	"encoding/json"
+)
+
+// serverMetadataFields are set by the API server, so they are not part of the desired state
+var serverMetadataFields = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// serverAnnotations are set by the API server or by kubectl, so they are not part of the desired state
+var serverAnnotations = []string{
+	"deployment.kubernetes.io/revision",
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// defaultValue is a field (by path, where "*" stands for any list element) that is ignored
+// when set to its default value for the given kinds.
+type defaultValue struct {
+	kinds map[string]bool
+	path  []string
+	value interface{}
+}
+
+func kinds(names ...string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
+
+// defaults lists the well known fields defaulted by the API server, as shown by `kubectl get -o yaml`
+var defaults = func() []defaultValue {
+	ds := []defaultValue{
+		{kinds("Deployment", "StatefulSet", "ReplicaSet", "ReplicationController"), []string{"spec", "replicas"}, 1},
+		{kinds("Deployment", "StatefulSet", "DaemonSet"), []string{"spec", "revisionHistoryLimit"}, 10},
+		{kinds("Deployment"), []string{"spec", "progressDeadlineSeconds"}, 600},
+		{kinds("StatefulSet"), []string{"spec", "podManagementPolicy"}, "OrderedReady"},
+		{kinds("Service"), []string{"spec", "type"}, "ClusterIP"},
+		{kinds("Service"), []string{"spec", "sessionAffinity"}, "None"},
+		{kinds("Service"), []string{"spec", "ports", "*", "protocol"}, "TCP"},
+	}
+
+	// pod templates, by kind
+	podSpecs := map[string][]string{
+		"Pod":                   {"spec"},
+		"Deployment":            {"spec", "template", "spec"},
+		"StatefulSet":           {"spec", "template", "spec"},
+		"DaemonSet":             {"spec", "template", "spec"},
+		"ReplicaSet":            {"spec", "template", "spec"},
+		"ReplicationController": {"spec", "template", "spec"},
+		"Job":                   {"spec", "template", "spec"},
+		"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+	podSpecDefaults := []defaultValue{
+		{nil, []string{"restartPolicy"}, "Always"},
+		{nil, []string{"terminationGracePeriodSeconds"}, 30},
+		{nil, []string{"dnsPolicy"}, "ClusterFirst"},
+		{nil, []string{"schedulerName"}, "default-scheduler"},
+	}
+	for _, containers := range []string{"containers", "initContainers"} {
+		podSpecDefaults = append(podSpecDefaults,
+			defaultValue{nil, []string{containers, "*", "terminationMessagePath"}, "/dev/termination-log"},
+			defaultValue{nil, []string{containers, "*", "terminationMessagePolicy"}, "File"},
+			defaultValue{nil, []string{containers, "*", "ports", "*", "protocol"}, "TCP"},
+		)
+	}
+	for kind, prefix := range podSpecs {
+		for _, d := range podSpecDefaults {
+			path := append(append([]string{}, prefix...), d.path...)
+			ds = append(ds, defaultValue{kinds(kind), path, d.value})
+		}
+	}
+	return ds
+}()
+
+// removeDefault removes the field at path from v (recursively), if its value equals value.
+func removeDefault(v interface{}, path []string, value interface{}) {
+	if len(path) == 0 {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if path[0] == "*" {
+			return
+		}
+		if len(path) == 1 {
+			if field, ok := v[path[0]]; ok && equal(field, value) {
+				delete(v, path[0])
+			}
+			return
+		}
+		removeDefault(v[path[0]], path[1:], value)
+	case []interface{}:
+		if path[0] != "*" {
+			return
+		}
+		for _, item := range v {
+			removeDefault(item, path[1:], value)
+		}
+	}
+}
+
+// equal compares a and b by their JSON encoding, so that numbers of different types can match.
+func equal(a interface{}, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
+
+// pruneEmpty removes null values, empty objects and empty lists from v (recursively),
+// and returns whether v itself is empty.
+func pruneEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for k, val := range v {
+			if pruneEmpty(val) {
+				delete(v, k)
+			}
+		}
+		return len(v) == 0
+	case []interface{}:
+		// list elements are positional, so they are pruned but never removed
+		for _, item := range v {
+			pruneEmpty(item)
+		}
+		return len(v) == 0
+	}
+	return false
+}
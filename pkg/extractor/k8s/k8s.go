@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// Scheme for Kubernetes manifests
+const Scheme = "k8s"
+
+func init() {
+	// options can be given by the URI's query
+	uri.RegisterQueryScheme(Scheme)
+}
+
+// ManifestKey is the metadata's key for storing the manifest of the objects
+const ManifestKey = "manifest"
+
+// PathKey is the metadata's key for the file or directory path
+const PathKey = "path"
+
+// ObjectKey is the metadata's key for storing the object's identity, for artifacts of single objects
+const ObjectKey = "object"
+
+var extensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+type opts struct {
+	split bool
+}
+
+// Artifact returns a k8s *api.Artifact for the Kubernetes objects defined within the file or
+// the directory (i.e. any .yaml, .yml or .json file, recursively) referenced by u.
+//
+// Objects are canonicalized (see Canonicalize), so that re-formatting does not change the hash
+// while any semantic change does. By default, a single artifact is returned for the whole set of objects,
+// hashed through a bundle.Manifest (where each object's path is its identity, see Object.ID).
+// If the split option is set (e.g. k8s://<path>?split=true), an artifact is returned for each object instead.
+func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
+
+	if u.Scheme != Scheme {
+		return nil, nil
+	}
+
+	opts := &opts{}
+	if err := extractor.Options(options).Apply(opts); err != nil {
+		return nil, err
+	}
+	if v := u.Query().Get("split"); v != "" {
+		split, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid split query parameter: %s", v)
+		}
+		opts.split = split
+	}
+
+	path, err := filepath.Abs(strings.TrimPrefix(u.Opaque, "//"))
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := readObjects(path, stat.IsDir())
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no Kubernetes objects found in %s", path)
+	}
+
+	if opts.split {
+		artifacts := make([]*api.Artifact, len(objects))
+		for i, o := range objects {
+			d, err := bundle.NewDescriptor(o.ID(), bytes.NewReader(o.Data))
+			if err != nil {
+				return nil, err
+			}
+			artifacts[i] = &api.Artifact{
+				Kind:        Scheme,
+				Name:        o.ID(),
+				Hash:        d.Digest.Encoded(),
+				Size:        d.Size,
+				ContentType: "application/json",
+				Metadata: api.Metadata{
+					ObjectKey: o.identity(),
+				},
+			}
+		}
+		return artifacts, nil
+	}
+
+	items := make([]bundle.Descriptor, len(objects))
+	var size uint64
+	for i, o := range objects {
+		d, err := bundle.NewDescriptor(o.ID(), bytes.NewReader(o.Data))
+		if err != nil {
+			return nil, err
+		}
+		items[i] = *d
+		size += d.Size
+	}
+	manifest := bundle.NewManifest(items...)
+	digest, err := manifest.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	return []*api.Artifact{{
+		Kind: Scheme,
+		Name: stat.Name(),
+		Hash: digest.Encoded(),
+		Size: size,
+		Metadata: api.Metadata{
+			ManifestKey: manifest,
+			PathKey:     path,
+			"objects":   len(objects),
+		},
+	}}, nil
+}
+
+// readObjects reads the objects defined within the file at path, or within the files
+// found by walking the directory at path. Objects are sorted by ID, and IDs must be unique.
+func readObjects(path string, isDir bool) ([]Object, error) {
+	filenames := []string{path}
+	if isDir {
+		filenames = []string{}
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if p != path && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.Mode().IsRegular() && extensions[strings.ToLower(filepath.Ext(p))] {
+				filenames = append(filenames, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objects := []Object{}
+	ids := map[string]string{}
+	for _, filename := range filenames {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		objs, err := Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", filename, err)
+		}
+		for _, o := range objs {
+			if other, ok := ids[o.ID()]; ok {
+				return nil, fmt.Errorf("%s: duplicate object %s (already defined in %s)", filename, o.ID(), other)
+			}
+			ids[o.ID()] = filename
+		}
+		objects = append(objects, objs...)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ID() < objects[j].ID() })
+	return objects, nil
+}
+
+// WithSplit returns a functional option to instruct the k8s's extractor to return an artifact
+// for each object, instead of a single artifact for the whole set.
+func WithSplit() extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.split = true
+		}
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package k8s
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+const testDeployment = `# web server
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  labels: {app: web, tier: frontend}
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+        ports:
+        - containerPort: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+`
+
+// same objects, re-formatted and as returned by the API server
+const testDeploymentFromServer = `apiVersion: v1
+kind: List
+items:
+- kind: Service
+  apiVersion: v1
+  metadata: {name: web, namespace: default, uid: 0f4a, resourceVersion: "1234", creationTimestamp: "2021-01-01T00:00:00Z"}
+  spec:
+    type: ClusterIP
+    sessionAffinity: None
+    ports: [{port: 80, protocol: TCP}]
+    selector: {app: web}
+  status:
+    loadBalancer: {}
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+    namespace: default
+    generation: 3
+    annotations:
+      deployment.kubernetes.io/revision: "2"
+    labels:
+      tier: frontend
+      app: web
+  spec:
+    replicas: 1
+    revisionHistoryLimit: 10
+    selector: {matchLabels: {app: web}}
+    template:
+      metadata:
+        creationTimestamp: null
+        labels: {app: web}
+      spec:
+        restartPolicy: Always
+        terminationGracePeriodSeconds: 30
+        containers:
+        - image: "nginx:1.19"
+          name: web
+          resources: {}
+          terminationMessagePolicy: File
+          ports:
+          - {containerPort: 80, protocol: TCP}
+  status:
+    replicas: 1
+`
+
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestArtifact(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "vcn-test-k8s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	original := writeTestFile(t, tmpDir, "original/all.yaml", testDeployment)
+	fromServer := writeTestFile(t, tmpDir, "server.yaml", testDeploymentFromServer)
+
+	extract := func(rawURI string) string {
+		u, _ := uri.Parse(rawURI)
+		artifacts, err := Artifact(u)
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 1)
+		assert.Equal(t, Scheme, artifacts[0].Kind)
+		return artifacts[0].Hash
+	}
+
+	// re-formatting does not change the hash
+	hash := extract("k8s://" + original)
+	assert.Equal(t, hash, extract("k8s://"+fromServer))
+	assert.Equal(t, hash, extract("k8s://"+filepath.Dir(original)))
+
+	// neither does splitting objects across files
+	parts := strings.Split(testDeployment, "---\n")
+	split := filepath.Join(tmpDir, "split")
+	writeTestFile(t, tmpDir, "split/deployment.yml", parts[0])
+	writeTestFile(t, tmpDir, "split/nested/service.yaml", parts[1])
+	writeTestFile(t, tmpDir, "split/README.md", "not a manifest")
+	assert.Equal(t, hash, extract("k8s://"+split))
+
+	u, _ := uri.Parse("k8s://" + split)
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	manifest, path := Metadata(*artifacts[0])
+	assert.Equal(t, split, path)
+	assert.Len(t, manifest.Items, 2)
+	assert.Equal(t, []string{"default/Deployment.apps/web"}, manifest.Items[0].Paths)
+	assert.Equal(t, []string{"default/Service/web"}, manifest.Items[1].Paths)
+	RemoveMetadata(artifacts[0])
+	assert.Nil(t, artifacts[0].Metadata[ManifestKey])
+	assert.Nil(t, artifacts[0].Metadata[PathKey])
+
+	// semantic changes do
+	changed := writeTestFile(t, tmpDir, "changed.yaml", strings.Replace(testDeployment, "nginx:1.19", "nginx:1.20", 1))
+	assert.NotEqual(t, hash, extract("k8s://"+changed))
+	changed = writeTestFile(t, tmpDir, "changed.yaml", strings.Replace(testDeployment, "port: 80\n", "port: 8080\n", 1))
+	assert.NotEqual(t, hash, extract("k8s://"+changed))
+	changed = writeTestFile(t, tmpDir, "changed.yaml", strings.Replace(testDeployment, "  selector:\n    app: web", "  type: NodePort\n  selector:\n    app: web", 1))
+	assert.NotEqual(t, hash, extract("k8s://"+changed))
+
+	// an artifact for each object
+	for _, rawURI := range []string{"k8s://" + original + "?split=true", "k8s://" + fromServer} {
+		u, _ := uri.Parse(rawURI)
+		artifacts, err := Artifact(u, WithSplit())
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 2)
+		assert.Equal(t, "default/Deployment.apps/web", artifacts[0].Name)
+		assert.Equal(t, manifest.Items[0].Digest.Encoded(), artifacts[0].Hash)
+		assert.Equal(t, "default/Service/web", artifacts[1].Name)
+		assert.Equal(t, manifest.Items[1].Digest.Encoded(), artifacts[1].Hash)
+		assert.Equal(t, "Service", artifacts[1].Metadata[ObjectKey].(map[string]interface{})["kind"])
+	}
+
+	// duplicate objects - ERROR
+	writeTestFile(t, tmpDir, "split/copy.yaml", parts[1])
+	u, _ = uri.Parse("k8s://" + split)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// not an object - ERROR
+	invalid := writeTestFile(t, tmpDir, "invalid.yaml", "- a\n- b\n")
+	u, _ = uri.Parse("k8s://" + invalid)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+
+	// missing name - ERROR
+	invalid = writeTestFile(t, tmpDir, "invalid.yaml", "apiVersion: v1\nkind: ConfigMap\n")
+	u, _ = uri.Parse("k8s://" + invalid)
+	_, err = Artifact(u)
+	assert.Error(t, err)
+}
+
+func TestCanonicalize(t *testing.T) {
+	objects, err := Decode(strings.NewReader(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+spec:
+  dnsPolicy: ClusterFirst
+  restartPolicy: Never
+  containers: [{name: c, image: "i", args: [], env: null}]
+`))
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "Pod/p", objects[0].ID())
+	assert.Equal(
+		t,
+		`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"p"},"spec":{"containers":[{"image":"i","name":"c"}],"restartPolicy":"Never"}}`,
+		string(objects[0].Data),
+	)
+}
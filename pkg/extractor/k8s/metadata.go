@@ -0,0 +1,25 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package k8s
+
+import (
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+	"github.com/vchain-us/vcn/pkg/extractor"
+)
+
+// Metadata extracts k8s related info from a.
+func Metadata(a api.Artifact) (manifest *bundle.Manifest, path string) {
+	return extractor.ManifestMetadata(a, Scheme, ManifestKey, PathKey)
+}
+
+// RemoveMetadata removes k8s related info from a.
+func RemoveMetadata(a *api.Artifact) {
+	extractor.RemoveManifestMetadata(a, Scheme, ManifestKey, PathKey)
+}
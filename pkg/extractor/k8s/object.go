@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Object is a canonicalized Kubernetes object.
+type Object struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Data is the canonical JSON encoding of the object
+	Data []byte
+}
+
+// ID returns the identity of o, i.e. "[<namespace>/]<kind>[.<group>]/<name>" (e.g. "default/Deployment.apps/web").
+func (o Object) ID() string {
+	id := o.Kind
+	if i := strings.Index(o.APIVersion, "/"); i >= 0 {
+		id += "." + o.APIVersion[:i]
+	}
+	id += "/" + o.Name
+	if o.Namespace != "" {
+		id = o.Namespace + "/" + id
+	}
+	return id
+}
+
+func (o Object) identity() map[string]interface{} {
+	m := map[string]interface{}{
+		"apiVersion": o.APIVersion,
+		"kind":       o.Kind,
+		"name":       o.Name,
+	}
+	if o.Namespace != "" {
+		m["namespace"] = o.Namespace
+	}
+	return m
+}
+
+// Decode reads the YAML (or JSON) documents from r and returns the canonicalized objects.
+// Empty documents are skipped, while the items of lists (i.e. kind "List") are returned as objects.
+func Decode(r io.Reader) ([]Object, error) {
+	objects := []Object{}
+	dec := yaml.NewDecoder(r)
+	for i := 0; ; i++ {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		obj, ok := normalize(doc).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document %d is not an object", i)
+		}
+		kind, _ := obj["kind"].(string)
+		if items, ok := obj["items"].([]interface{}); ok && strings.HasSuffix(kind, "List") {
+			for j, item := range items {
+				itemObj, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("document %d, item %d is not an object", i, j)
+				}
+				o, err := newObject(itemObj)
+				if err != nil {
+					return nil, fmt.Errorf("document %d, item %d: %s", i, j, err)
+				}
+				objects = append(objects, *o)
+			}
+			continue
+		}
+		o, err := newObject(obj)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %s", i, err)
+		}
+		objects = append(objects, *o)
+	}
+	return objects, nil
+}
+
+func newObject(obj map[string]interface{}) (*Object, error) {
+	o := &Object{}
+	o.APIVersion, _ = obj["apiVersion"].(string)
+	o.Kind, _ = obj["kind"].(string)
+	if o.APIVersion == "" || o.Kind == "" {
+		return nil, fmt.Errorf("apiVersion and kind are required")
+	}
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		o.Name, _ = metadata["name"].(string)
+		o.Namespace, _ = metadata["namespace"].(string)
+	}
+	if o.Name == "" {
+		return nil, fmt.Errorf("metadata.name is required for %s", o.Kind)
+	}
+
+	var err error
+	if o.Data, err = Canonicalize(obj); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// normalize converts the maps decoded by yaml (i.e. map[interface{}]interface{}) to map[string]interface{},
+// so that they can be JSON encoded.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	}
+	return v
+}
+
+// Canonicalize returns the canonical JSON encoding of the Kubernetes object obj (i.e. with sorted keys and
+// no insignificant whitespace). The status and the fields set by the API server (e.g. metadata.uid or
+// metadata.managedFields) are stripped, as well as defaulted fields, i.e.:
+//   - null values, empty objects and empty lists
+//   - well known fields set to their default value (see defaults), such as a Deployment's spec.replicas set to 1
+//
+// obj is modified in place.
+func Canonicalize(obj map[string]interface{}) ([]byte, error) {
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, k := range serverMetadataFields {
+			delete(metadata, k)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for _, k := range serverAnnotations {
+				delete(annotations, k)
+			}
+		}
+	}
+
+	kind, _ := obj["kind"].(string)
+	for _, d := range defaults {
+		if d.kinds[kind] {
+			removeDefault(obj, d.path, d.value)
+		}
+	}
+
+	pruneEmpty(obj)
+	return json.Marshal(obj)
+}
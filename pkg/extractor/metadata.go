@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package extractor
+
+import (
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+)
+
+// ManifestMetadata returns the manifest and the path stored within the metadata of a by the extractor of
+// the given kind, under manifestKey and pathKey. Nothing is returned if a is not of the given kind.
+func ManifestMetadata(a api.Artifact, kind string, manifestKey string, pathKey string) (manifest *bundle.Manifest, path string) {
+	if a.Kind != kind {
+		return
+	}
+	if m, ok := a.Metadata[manifestKey].(*bundle.Manifest); ok {
+		manifest = m
+	}
+	if p, ok := a.Metadata[pathKey].(string); ok {
+		path = p
+	}
+	return
+}
+
+// RemoveManifestMetadata removes the manifest and the path stored within the metadata of a by the extractor of
+// the given kind, under manifestKey and pathKey, if a is of the given kind.
+func RemoveManifestMetadata(a *api.Artifact, kind string, manifestKey string, pathKey string) {
+	if a == nil || a.Kind != kind {
+		return
+	}
+	delete(a.Metadata, manifestKey)
+	delete(a.Metadata, pathKey)
+}
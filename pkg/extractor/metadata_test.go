@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package extractor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/bundle"
+)
+
+func TestManifestMetadata(t *testing.T) {
+	manifest := bundle.NewManifest()
+	a := api.Artifact{
+		Kind: "dir",
+		Metadata: api.Metadata{
+			"manifest": manifest,
+			"path":     "/src",
+			"other":    "value",
+		},
+	}
+
+	m, path := ManifestMetadata(a, "dir", "manifest", "path")
+	assert.Same(t, manifest, m)
+	assert.Equal(t, "/src", path)
+
+	// another kind
+	m, path = ManifestMetadata(a, "git", "manifest", "path")
+	assert.Nil(t, m)
+	assert.Empty(t, path)
+	RemoveManifestMetadata(&a, "git", "manifest", "path")
+	assert.Len(t, a.Metadata, 3)
+
+	RemoveManifestMetadata(&a, "dir", "manifest", "path")
+	assert.Equal(t, api.Metadata{"other": "value"}, a.Metadata)
+	m, path = ManifestMetadata(a, "dir", "manifest", "path")
+	assert.Nil(t, m)
+	assert.Empty(t, path)
+
+	RemoveManifestMetadata(nil, "dir", "manifest", "path")
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+// Package plugin provides external extractors, i.e. executables named vcn-extractor-<scheme>.
+//
+// The protocol is JSON based: the executable is run with a Request written to its stdin,
+// and it must write the extracted artifacts (i.e. a JSON encoded []api.Artifact) to its stdout,
+// then exit with status zero. On failure, the executable must exit with a non-zero status,
+// and its stderr is reported as the error message.
+//
+// A request looks like:
+//
+//	{"version":1,"uri":"firmware://image.bin?board=rev2"}
+//
+// while a response looks like:
+//
+//	[{"kind":"firmware","name":"image.bin","hash":"<sha256 hex digest>","size":1024,"metadata":{"board":"rev2"}}]
+//
+// Functional options are not passed to external extractors, so options must be carried by the URI's query.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/vchain-us/vcn/pkg/api"
+	"github.com/vchain-us/vcn/pkg/extractor"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+// ExecutablePrefix is the prefix of the name of external extractors' executables
+const ExecutablePrefix = "vcn-extractor-"
+
+// ProtocolVersion is the version of the protocol spoken with external extractors
+const ProtocolVersion = 1
+
+// Timeout is the maximum time an external extractor is allowed to run, after which it is killed.
+var Timeout = 10 * time.Minute
+
+var (
+	schemeRe = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+	hashRe   = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// Request is sent to the external extractor.
+type Request struct {
+	Version int    `json:"version"`
+	URI     string `json:"uri"`
+}
+
+// Lookup returns the path of the external extractor's executable for scheme, looking for it within dirs
+// and then within the PATH environment variable's directories.
+// When the executable is found more than once, the first one wins (as the shell does).
+func Lookup(scheme string, dirs ...string) (string, bool) {
+	if !schemeRe.MatchString(scheme) {
+		return "", false
+	}
+	name := ExecutablePrefix + scheme
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	for _, d := range dirs {
+		if d == "" {
+			continue
+		}
+		path := filepath.Join(d, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if s, ok := schemeOf(info); ok && s == scheme {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Resolver returns a function resolving the external extractor for a scheme (see Lookup), if any,
+// to be registered by extractor.RegisterFallback, so that executables are looked up only when needed.
+func Resolver(dirs ...string) func(scheme string) extractor.Extractor {
+	return func(scheme string) extractor.Extractor {
+		path, ok := Lookup(scheme, dirs...)
+		if !ok {
+			return nil
+		}
+		return Extractor(scheme, path)
+	}
+}
+
+// schemeOf returns the scheme handled by the executable described by info, if any.
+func schemeOf(info os.FileInfo) (string, bool) {
+	name := info.Name()
+	if !strings.HasPrefix(name, ExecutablePrefix) || info.IsDir() {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	} else if info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	scheme := strings.TrimPrefix(name, ExecutablePrefix)
+	return scheme, schemeRe.MatchString(scheme)
+}
+
+// Extractor returns an extractor.Extractor running the executable at path for the given scheme.
+func Extractor(scheme string, path string) extractor.Extractor {
+	return func(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error) {
+		if u.Scheme != scheme {
+			return nil, nil
+		}
+		return run(path, u)
+	}
+}
+
+func run(path string, u *uri.URI) ([]*api.Artifact, error) {
+	req, err := json.Marshal(Request{
+		Version: ProtocolVersion,
+		URI:     u.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), fmt.Sprintf("VCN_EXTRACTOR_PROTOCOL=%d", ProtocolVersion))
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s extractor timed out after %s", u.Scheme, Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s extractor failed: %s", u.Scheme, msg)
+		}
+		return nil, fmt.Errorf("%s extractor failed: %s", u.Scheme, err)
+	}
+
+	artifacts := []*api.Artifact{}
+	if err := json.Unmarshal(stdout.Bytes(), &artifacts); err != nil {
+		return nil, fmt.Errorf("%s extractor returned an invalid response: %s", u.Scheme, err)
+	}
+	for i, a := range artifacts {
+		if err := validate(a, u.Scheme); err != nil {
+			return nil, fmt.Errorf("%s extractor returned an invalid artifact at index %d: %s", u.Scheme, i, err)
+		}
+	}
+	return artifacts, nil
+}
+
+// validate checks the artifact a returned by the extractor for scheme, defaulting its kind to scheme.
+func validate(a *api.Artifact, scheme string) error {
+	if a == nil {
+		return fmt.Errorf("null artifact")
+	}
+	if a.Kind == "" {
+		a.Kind = scheme
+	}
+	if a.Name == "" {
+		return fmt.Errorf("name is missing")
+	}
+	a.Hash = strings.ToLower(a.Hash)
+	if !hashRe.MatchString(a.Hash) {
+		return fmt.Errorf("hash must be a hex encoded sha256 digest: %q", a.Hash)
+	}
+	return nil
+}
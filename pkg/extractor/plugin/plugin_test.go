@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vchain-us/vcn/pkg/uri"
+)
+
+const testHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func writeExecutable(t *testing.T, dir string, name string, script string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "vcn-test-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// echoes the requested URI as name
+	path := writeExecutable(t, dir, ExecutablePrefix+"firmware", `
+uri=$(cat | sed 's/.*"uri":"\([^"]*\)".*/\1/')
+echo '[{"name":"'$uri'","hash":"`+testHash+`","size":1,"metadata":{"board":"rev2"}}]'
+`)
+	writeExecutable(t, dir, ExecutablePrefix+"failing", "echo 'no such image' >&2; exit 1\n")
+	writeExecutable(t, dir, ExecutablePrefix+"invalid", "echo '[{\"name\":\"x\",\"hash\":\"abc\"}]'\n")
+	// not executable
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ExecutablePrefix+"noexec"), []byte{}, 0644))
+
+	found, ok := Lookup("firmware", dir)
+	assert.True(t, ok)
+	assert.Equal(t, path, found)
+	_, ok = Lookup("noexec", dir)
+	assert.False(t, ok)
+	_, ok = Lookup("missing", dir)
+	assert.False(t, ok)
+	_, ok = Lookup("../firmware", dir)
+	assert.False(t, ok)
+	assert.Nil(t, Resolver(dir)("missing"))
+
+	e := Resolver(dir)("firmware")
+	assert.NotNil(t, e)
+	u, _ := uri.Parse("firmware://image.bin?board=rev2")
+	artifacts, err := e(u)
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+	assert.Equal(t, "firmware", artifacts[0].Kind)
+	assert.Equal(t, "firmware://image.bin?board=rev2", artifacts[0].Name)
+	assert.Equal(t, testHash, artifacts[0].Hash)
+	assert.Equal(t, uint64(1), artifacts[0].Size)
+	assert.Equal(t, "rev2", artifacts[0].Metadata["board"])
+
+	// other schemes are not handled
+	u, _ = uri.Parse("file://image.bin")
+	artifacts, err = e(u)
+	assert.NoError(t, err)
+	assert.Nil(t, artifacts)
+
+	// failing extractor - ERROR
+	u, _ = uri.Parse("failing://image")
+	_, err = Resolver(dir)("failing")(u)
+	assert.EqualError(t, err, "failing extractor failed: no such image")
+
+	// invalid hash - ERROR
+	u, _ = uri.Parse("invalid://image")
+	_, err = Resolver(dir)("invalid")(u)
+	assert.Error(t, err)
+
+	// hung extractor - ERROR
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = 100 * time.Millisecond
+	writeExecutable(t, dir, ExecutablePrefix+"hung", "exec sleep 10\n")
+	u, _ = uri.Parse("hung://image")
+	start := time.Now()
+	_, err = Resolver(dir)("hung")(u)
+	assert.EqualError(t, err, "hung extractor timed out after 100ms")
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
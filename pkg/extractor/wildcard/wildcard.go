@@ -10,8 +10,10 @@ package wildcard
 
 import (
 	"errors"
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
 	"github.com/vchain-us/vcn/pkg/extractor/dir"
 	"github.com/vchain-us/vcn/pkg/extractor/file"
+	"github.com/vchain-us/vcn/pkg/extractor/internal/parallel"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,6 +36,12 @@ type opts struct {
 	initIgnoreFile    bool
 	skipIgnoreFileErr bool
 	recursive         bool
+	archive           bool
+	gitIgnore         bool
+	jobs              int
+	hashCache         dir.HashCache
+	manifestVersion   uint
+	digests           []string
 }
 
 // Artifact returns a file *api.Artifact from a given u
@@ -58,17 +66,23 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 	// provided path is a file
 	if fileInfo, err := os.Stat(p); err == nil {
 		if !fileInfo.IsDir() {
-			u, err := uri.Parse("file://" + p)
-			if err != nil {
-				return nil, err
-			}
-			return file.Artifact(u)
+			return fileArtifact(p, opts)
 		}
 		u, err := uri.Parse("dir://" + p)
 		if err != nil {
 			return nil, err
 		}
-		return dir.Artifact(u)
+		dirOptions := []extractor.Option{dir.WithJobs(opts.jobs)}
+		if opts.gitIgnore {
+			dirOptions = append(dirOptions, dir.WithGitIgnore())
+		}
+		if opts.hashCache != nil {
+			dirOptions = append(dirOptions, dir.WithHashCache(opts.hashCache))
+		}
+		if opts.manifestVersion != 0 {
+			dirOptions = append(dirOptions, dir.WithManifestVersion(opts.manifestVersion))
+		}
+		return dir.Artifact(u, dirOptions...)
 	}
 
 	root := filepath.Dir(p)
@@ -103,21 +117,45 @@ func Artifact(u *uri.URI, options ...extractor.Option) ([]*api.Artifact, error)
 		return nil, errors.New("no files matching from provided search terms")
 	}
 
+	// convert files path list to artifacts, keeping the list order
+	results := make([][]*api.Artifact, len(filePaths))
+	err = parallel.Do(opts.jobs, len(filePaths), func(i int) (err error) {
+		results[i], err = fileArtifact(filePaths[i], opts)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
 	arst := []*api.Artifact{}
-	// convert files path list to artifacts
-	for _, fp := range filePaths {
-		u, err := uri.Parse("file://" + fp)
+	for _, ars := range results {
+		arst = append(arst, ars...)
+	}
+
+	return arst, nil
+}
+
+// fileArtifact returns the artifact for the file at path, processing it as an archive
+// only when enabled by opts and the file format is supported.
+func fileArtifact(path string, opts *opts) ([]*api.Artifact, error) {
+	scheme := file.Scheme
+	if opts.archive {
+		format, err := archive.DetectFormat(path)
 		if err != nil {
 			return nil, err
 		}
-		ars, err := file.Artifact(u)
-		if err != nil {
-			return nil, err
+		if format != "" {
+			scheme = archive.Scheme
 		}
-		arst = append(arst, ars...)
 	}
 
-	return arst, nil
+	u, err := uri.Parse(scheme + "://" + path)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == archive.Scheme {
+		return archive.Artifact(u)
+	}
+	return file.Artifact(u, file.WithDigests(opts.digests...))
 }
 
 func buildFilePaths(wildcard string, filePaths *[]string) func(ele string, info os.FileInfo, err error) error {
@@ -152,3 +190,66 @@ func WithRecursive() extractor.Option {
 		return nil
 	}
 }
+
+// WithArchive wildcard usage will process supported archives (tar, tar.gz, zip) by their content
+// instead of their raw bytes
+func WithArchive() extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.archive = true
+		}
+		return nil
+	}
+}
+
+// WithGitIgnore wildcard usage will honour .gitignore files when the provided path is a directory
+func WithGitIgnore() extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.gitIgnore = true
+		}
+		return nil
+	}
+}
+
+// WithJobs wildcard usage will hash up to jobs files concurrently (a value less than or equal to zero
+// means one job per CPU)
+func WithJobs(jobs int) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.jobs = jobs
+		}
+		return nil
+	}
+}
+
+// WithHashCache wildcard usage will reuse the digests cached by c when the provided path is a directory
+func WithHashCache(c dir.HashCache) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.hashCache = c
+		}
+		return nil
+	}
+}
+
+// WithManifestVersion wildcard usage will use the given manifest schema version when the provided path is a directory
+func WithManifestVersion(version uint) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.manifestVersion = version
+		}
+		return nil
+	}
+}
+
+// WithDigests wildcard usage will compute the digests of the given algos, in addition to the primary one,
+// for each matching file
+func WithDigests(algos ...string) extractor.Option {
+	return func(o interface{}) error {
+		if o, ok := o.(*opts); ok {
+			o.digests = append(o.digests, algos...)
+		}
+		return nil
+	}
+}
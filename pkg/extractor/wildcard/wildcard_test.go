@@ -1,6 +1,9 @@
 package wildcard
 
 import (
+	"archive/zip"
+
+	"github.com/vchain-us/vcn/pkg/extractor/archive"
 	file2 "github.com/vchain-us/vcn/pkg/extractor/file"
 	"log"
 
@@ -38,3 +41,29 @@ func TestWildcard(t *testing.T) {
 	assert.NotNil(t, artifacts)
 	assert.Equal(t, artifacts[0].ContentType, "application/pdf")
 }
+
+func TestWildcardArchive(t *testing.T) {
+	file, err := ioutil.TempFile("", "vcn-test-scheme-archive")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	zw := zip.NewWriter(file)
+	fw, err := zw.Create("file.txt")
+	assert.NoError(t, err)
+	_, err = fw.Write([]byte("123\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, file.Close())
+
+	u, _ := uri.Parse(file.Name())
+
+	// archive detection is disabled by default
+	artifacts, err := Artifact(u)
+	assert.NoError(t, err)
+	assert.Equal(t, file2.Scheme, artifacts[0].Kind)
+
+	artifacts, err = Artifact(u, WithArchive())
+	assert.NoError(t, err)
+	assert.Equal(t, archive.Scheme, artifacts[0].Kind)
+}
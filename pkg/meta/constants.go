@@ -44,6 +44,7 @@ const (
 	StatusUnknown       Status = 2
 	StatusUnsupported   Status = 3
 	StatusApikeyRevoked Status = 4
+	StatusExpired       Status = 5
 )
 
 // Allowed Visibility values
@@ -148,6 +149,8 @@ func (s Status) String() string {
 		return "UNSUPPORTED"
 	case StatusApikeyRevoked:
 		return "REVOKED"
+	case StatusExpired:
+		return "EXPIRED"
 	default:
 		log.Fatal("unsupported status: ", int64(s))
 		return ""
@@ -189,4 +192,8 @@ const IndexDateRangePrefix = "_INDEX.ITEM.INSERTION-DATE."
 
 const VcnDefaultExitCode = 0
 
+// VcnPolicyFailedExitCode is the exit code used when trusted assets do not satisfy the verification policy.
+// It differs from the exit codes of all statuses, so a policy failure can be told apart from a not trusted asset.
+const VcnPolicyFailedExitCode = 6
+
 const AttachmentSeparator = ".attach."
@@ -21,6 +21,8 @@ func StatusColor(status Status) (color.Attribute, color.Attribute, color.Attribu
 		return StyleWarning()
 	case StatusApikeyRevoked:
 		return StyleWarning()
+	case StatusExpired:
+		return StyleWarning()
 	default:
 		return StyleError()
 	}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status   Status
+		name     string
+		exitCode int
+		style    func() (color.Attribute, color.Attribute, color.Attribute)
+	}{
+		{StatusTrusted, "TRUSTED", 0, StyleSuccess},
+		{StatusUntrusted, "UNTRUSTED", 1, StyleError},
+		{StatusUnknown, "UNKNOWN", 2, StyleWarning},
+		{StatusUnsupported, "UNSUPPORTED", 3, StyleError},
+		{StatusApikeyRevoked, "REVOKED", 4, StyleWarning},
+		{StatusExpired, "EXPIRED", 5, StyleWarning},
+	} {
+		assert.Equal(t, tc.name, tc.status.String())
+		assert.Equal(t, tc.exitCode, tc.status.Int())
+
+		c, s, b := StatusColor(tc.status)
+		ec, es, eb := tc.style()
+		assert.Equal(t, []color.Attribute{ec, es, eb}, []color.Attribute{c, s, b}, tc.name)
+	}
+}
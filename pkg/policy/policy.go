@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// AnyValue is the metadata value matching any value, as long as the attribute is present
+const AnyValue = "*"
+
+// Rule names, as reported by violations
+const (
+	RuleSigners     = "signers"
+	RuleMaxAge      = "maxAge"
+	RuleMetadata    = "metadata"
+	RuleAttachments = "attachments"
+)
+
+// Signers requires that at least Min of the listed IDs notarized the artifact
+type Signers struct {
+	IDs []string `json:"ids" yaml:"ids"`
+	Min int      `json:"min,omitempty" yaml:"min,omitempty"`
+}
+
+// Policy is a set of rules an authenticated artifact must comply with.
+// Only the rules that are set are evaluated.
+type Policy struct {
+	Signers     *Signers          `json:"signers,omitempty" yaml:"signers,omitempty"`
+	MaxAge      string            `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Attachments []string          `json:"attachments,omitempty" yaml:"attachments,omitempty"`
+
+	maxAge time.Duration
+}
+
+// Subject holds the facts about an authenticated artifact a policy is evaluated against.
+type Subject struct {
+	// Signers holds the IDs of the signers with a trusted notarization of the artifact
+	Signers []string
+	// Timestamp is the time of the notarization
+	Timestamp time.Time
+	// Metadata holds the notarized metadata
+	Metadata map[string]interface{}
+	// Attachments holds the labels of the attachments found
+	Attachments []string
+}
+
+// Violation describes a failed rule.
+type Violation struct {
+	Rule    string `json:"rule" yaml:"rule"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Report is the result of a policy evaluation.
+type Report struct {
+	Passed     bool        `json:"passed" yaml:"passed"`
+	Violations []Violation `json:"violations,omitempty" yaml:"violations,omitempty"`
+}
+
+// Load reads and validates the policy at path, which can be either in YAML or JSON format.
+func Load(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %s", path, err)
+	}
+	return p, nil
+}
+
+// Parse decodes and validates a policy from b.
+func Parse(b []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(b, p); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Policy) validate() error {
+	if s := p.Signers; s != nil {
+		if len(s.IDs) == 0 {
+			return fmt.Errorf("signers: no ids listed")
+		}
+		if s.Min == 0 {
+			s.Min = 1
+		}
+		if n := len(uniq(s.IDs)); s.Min < 0 || s.Min > n {
+			return fmt.Errorf("signers: min must be between 1 and the number of listed ids (%d)", n)
+		}
+	}
+	if p.MaxAge != "" {
+		d, err := parseAge(p.MaxAge)
+		if err != nil {
+			return fmt.Errorf("maxAge: %s", err)
+		}
+		p.maxAge = d
+	}
+	for _, label := range p.Attachments {
+		if label == "" {
+			return fmt.Errorf("attachments: empty label")
+		}
+	}
+	return nil
+}
+
+// parseAge parses a Go duration, also accepting a number of days (eg. "30d").
+func parseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive: %s", s)
+	}
+	return d, nil
+}
+
+// Evaluate checks s against all rules of p, at the given time, and reports every violation found.
+func (p *Policy) Evaluate(s Subject, now time.Time) *Report {
+	r := &Report{}
+	fail := func(rule string, format string, a ...interface{}) {
+		r.Violations = append(r.Violations, Violation{Rule: rule, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if p.Signers != nil {
+		found := map[string]bool{}
+		for _, id := range s.Signers {
+			found[id] = true
+		}
+		count := 0
+		for _, id := range uniq(p.Signers.IDs) {
+			if found[id] {
+				count++
+			}
+		}
+		if count < p.Signers.Min {
+			fail(RuleSigners, "notarized by %d of the listed signers, at least %d required", count, p.Signers.Min)
+		}
+	}
+
+	if p.maxAge > 0 {
+		switch {
+		case s.Timestamp.IsZero():
+			fail(RuleMaxAge, "notarization time is not available")
+		case now.Sub(s.Timestamp) > p.maxAge:
+			fail(RuleMaxAge, "notarized %s ago, older than %s", now.Sub(s.Timestamp).Truncate(time.Second), p.MaxAge)
+		}
+	}
+
+	keys := make([]string, 0, len(p.Metadata))
+	for k := range p.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		expected := p.Metadata[k]
+		v, ok := lookup(s.Metadata, k)
+		switch {
+		case !ok:
+			fail(RuleMetadata, "%s is missing", k)
+		case expected != AnyValue && fmt.Sprint(v) != expected:
+			fail(RuleMetadata, "%s is %v, %s required", k, v, expected)
+		}
+	}
+
+	labels := map[string]bool{}
+	for _, l := range s.Attachments {
+		labels[l] = true
+	}
+	for _, l := range p.Attachments {
+		if !labels[l] {
+			fail(RuleAttachments, "no attachment labeled %s", l)
+		}
+	}
+
+	r.Passed = len(r.Violations) == 0
+	return r
+}
+
+// lookup returns the value of key within m. Keys of nested objects can be joined by dots,
+// but a key that matches as is takes precedence.
+func lookup(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) < 2 {
+		return nil, false
+	}
+	switch nested := m[parts[0]].(type) {
+	case map[string]interface{}:
+		return lookup(nested, parts[1])
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(nested))
+		for k, v := range nested {
+			converted[fmt.Sprint(k)] = v
+		}
+		return lookup(converted, parts[1])
+	}
+	return nil, false
+}
+
+func uniq(ss []string) []string {
+	seen := make(map[string]bool, len(ss))
+	res := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			res = append(res, s)
+		}
+	}
+	return res
+}
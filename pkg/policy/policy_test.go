@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `
+signers:
+  ids: [alice, bob, carol]
+  min: 2
+maxAge: 30d
+metadata:
+  VCN_CI_ENV: gitlab
+  version: "*"
+  package.name: vcn
+attachments:
+  - sbom
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.Signers.Min)
+	assert.Equal(t, 30*24*time.Hour, p.maxAge)
+
+	// min defaults to 1
+	p, err = Parse([]byte("signers:\n  ids: [alice]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.Signers.Min)
+
+	// JSON is accepted too
+	p, err = Parse([]byte(`{"maxAge": "12h"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, p.maxAge)
+
+	for _, invalid := range []string{
+		"signers:\n  min: 1\n",
+		"signers:\n  ids: [alice, alice]\n  min: 2\n",
+		"maxAge: forever\n",
+		"maxAge: -1h\n",
+		"attachments: ['']\n",
+		"unknown: rule\n",
+	} {
+		_, err := Parse([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	s := Subject{
+		Signers:   []string{"alice", "carol", "mallory"},
+		Timestamp: now.Add(-24 * time.Hour),
+		Metadata: map[string]interface{}{
+			"VCN_CI_ENV": "gitlab",
+			"version":    "1.0.0",
+			"package":    map[string]interface{}{"name": "vcn"},
+		},
+		Attachments: []string{"sbom", "scan"},
+	}
+	r := p.Evaluate(s, now)
+	assert.True(t, r.Passed)
+	assert.Empty(t, r.Violations)
+
+	s = Subject{
+		Signers:   []string{"alice", "alice", "mallory"},
+		Timestamp: now.Add(-31 * 24 * time.Hour),
+		Metadata: map[string]interface{}{
+			"VCN_CI_ENV": "github",
+		},
+	}
+	r = p.Evaluate(s, now)
+	assert.False(t, r.Passed)
+	if assert.Len(t, r.Violations, 6) {
+		assert.Equal(t, RuleSigners, r.Violations[0].Rule)
+		assert.Equal(t, "notarized by 1 of the listed signers, at least 2 required", r.Violations[0].Message)
+		assert.Equal(t, RuleMaxAge, r.Violations[1].Rule)
+		assert.Equal(t, "metadata: VCN_CI_ENV is github, gitlab required", r.Violations[2].String())
+		assert.Equal(t, "metadata: package.name is missing", r.Violations[3].String())
+		assert.Equal(t, "metadata: version is missing", r.Violations[4].String())
+		assert.Equal(t, "attachments: no attachment labeled sbom", r.Violations[5].String())
+	}
+
+	// an empty policy always passes
+	r = (&Policy{}).Evaluate(Subject{}, now)
+	assert.True(t, r.Passed)
+
+	// the notarization time is required by maxAge
+	p, err = Parse([]byte("maxAge: 1h"))
+	assert.NoError(t, err)
+	r = p.Evaluate(Subject{}, now)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "maxAge: notarization time is not available", r.Violations[0].String())
+}
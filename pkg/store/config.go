@@ -34,6 +34,7 @@ type ConfigRoot struct {
 	SchemaVersion  uint           `json:"schemaVersion"`
 	Users          []*User        `json:"users"`
 	CurrentContext CurrentContext `json:"currentContext"`
+	HashCache      bool           `json:"hashCache,omitempty"`
 }
 
 type CurrentContext struct {
@@ -130,6 +131,7 @@ func SaveConfig() error {
 	v.Set("users", cfg.Users)
 	v.Set("currentContext", cfg.CurrentContext)
 	v.Set("schemaVersion", cfg.SchemaVersion)
+	v.Set("hashCache", cfg.HashCache)
 	return v.WriteConfig()
 }
 
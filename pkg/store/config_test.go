@@ -54,6 +54,7 @@ func TestSaveConfig(t *testing.T) {
 				KeyStore: filepath.Join(tdir, "u", email, "k"),
 			},
 		},
+		HashCache: true,
 	}
 
 	err := SaveConfig()
@@ -61,6 +62,7 @@ func TestSaveConfig(t *testing.T) {
 
 	LoadConfig()
 	assert.Equal(t, email, Config().CurrentContext.Email)
+	assert.True(t, HashCacheEnabled())
 }
 
 func TestConfigClearContext(t *testing.T) {
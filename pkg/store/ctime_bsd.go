@@ -0,0 +1,25 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package store
+
+import (
+	"os"
+	"syscall"
+)
+
+// changeTime returns the status change time (ctime) of the file described by info, in nanoseconds.
+func changeTime(info os.FileInfo) int64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return st.Ctimespec.Nano()
+	}
+	return 0
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin && !freebsd && !netbsd
+// +build !linux,!darwin,!freebsd,!netbsd
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package store
+
+import (
+	"os"
+)
+
+// changeTime returns the status change time (ctime) of the file described by info.
+// It is not available on this platform, so 0 is returned.
+func changeTime(info os.FileInfo) int64 {
+	return 0
+}
@@ -0,0 +1,22 @@
+//go:build windows || plan9
+// +build windows plan9
+
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package store
+
+import (
+	"os"
+)
+
+// fileID returns the inode and device numbers of the file described by info.
+// They are not available on this platform, so size and mtime only are used to detect changes.
+func fileID(info os.FileInfo) (inode uint64, device uint64) {
+	return 0, 0
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package store
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// racyThreshold is the minimum age of a file's modification and change times for its digest to be cached.
+// Files modified more recently could be modified again without their times changing.
+var racyThreshold = 2 * time.Second
+
+// HashCacheEnabled returns true if the hash cache has been enabled within the global config.
+func HashCacheEnabled() bool {
+	return cfg != nil && cfg.HashCache
+}
+
+// HashCacheEntry holds the digest of a file along with the file's attributes it was computed for.
+type HashCacheEntry struct {
+	Size       int64         `json:"size"`
+	ModTime    int64         `json:"mtime"`
+	ChangeTime int64         `json:"ctime,omitempty"`
+	Inode      uint64        `json:"inode,omitempty"`
+	Device     uint64        `json:"device,omitempty"`
+	Digest     digest.Digest `json:"digest"`
+}
+
+func newHashCacheEntry(info os.FileInfo, d digest.Digest) HashCacheEntry {
+	inode, device := fileID(info)
+	return HashCacheEntry{
+		Size:       info.Size(),
+		ModTime:    info.ModTime().UnixNano(),
+		ChangeTime: changeTime(info),
+		Inode:      inode,
+		Device:     device,
+		Digest:     d,
+	}
+}
+
+// matches returns true if info describes the same file, unchanged, the entry was computed for.
+func (e HashCacheEntry) matches(info os.FileInfo) bool {
+	return e == newHashCacheEntry(info, e.Digest)
+}
+
+// HashCache is a cache of file digests keyed by absolute path. A cached digest is reused only if
+// the file's size, mtime, ctime, inode and device are unchanged. Since the ctime cannot be set by users,
+// in-place edits that preserve the mtime (e.g. touch -r) are detected too. It is safe for concurrent use.
+type HashCache struct {
+	mu      sync.Mutex
+	entries map[string]HashCacheEntry
+	dirty   bool
+}
+
+// HashCacheFilepath returns the hash cache file path (e.g. /tmp/.vcn/cache/hashes.json)
+func HashCacheFilepath() (string, error) {
+	path := filepath.Join(dir, defaultCacheDir)
+	if err := ensureDir(path); err != nil {
+		return "", err
+	}
+	return filepath.Join(path, hashCacheFilename), nil
+}
+
+// LoadHashCache returns the hash cache stored in the working directory.
+// The cache is a performance optimization only, so an empty cache is returned if it cannot be read.
+func LoadHashCache() *HashCache {
+	c := &HashCache{
+		entries: make(map[string]HashCacheEntry),
+	}
+	path, err := HashCacheFilepath()
+	if err != nil {
+		return c
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]HashCacheEntry)
+	}
+	return c
+}
+
+// Get returns the cached digest of the file at path, if info still matches the cached attributes.
+func (c *HashCache) Get(path string, info os.FileInfo) (digest.Digest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[path]
+	if !ok || !e.matches(info) {
+		return "", false
+	}
+	return e.Digest, true
+}
+
+// Put caches the digest d of the file at path, where info are the file's attributes before hashing.
+// Recently modified files are not cached (see racyThreshold).
+func (c *HashCache) Put(path string, info os.FileInfo, d digest.Digest) {
+	if time.Since(info.ModTime()) < racyThreshold || time.Since(time.Unix(0, changeTime(info))) < racyThreshold {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = newHashCacheEntry(info, d)
+	c.dirty = true
+}
+
+// Save writes the cache into the working directory, if it has been modified.
+func (c *HashCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	path, err := HashCacheFilepath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, FilePerm); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// Prune removes the entries of files that no longer exist or have changed,
+// and returns the number of entries removed.
+func (c *HashCache) Prune() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := 0
+	for path, e := range c.entries {
+		if info, err := os.Stat(path); err != nil || !e.matches(info) {
+			delete(c.entries, path)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.dirty = true
+	}
+	return removed
+}
+
+// Clear removes all the entries and returns the number of entries removed.
+func (c *HashCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := len(c.entries)
+	if removed > 0 {
+		c.entries = make(map[string]HashCacheEntry)
+		c.dirty = true
+	}
+	return removed
+}
+
+// Len returns the number of cached entries.
+func (c *HashCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
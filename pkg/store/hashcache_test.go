@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018-2020 vChain, Inc. All Rights Reserved.
+ * This software is released under GPL3.
+ * The full license information can be found under:
+ * https://www.gnu.org/licenses/gpl-3.0.en.html
+ *
+ */
+
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashCache(t *testing.T) {
+	tdir, err := ioutil.TempDir("", "vcn-test-store-hashcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tdir)
+	SetDir(filepath.Join(tdir, DefaultDirName))
+
+	// the ctime cannot be set, so files are cached shortly after being written
+	defer func(d time.Duration) { racyThreshold = d }(racyThreshold)
+	racyThreshold = 50 * time.Millisecond
+
+	path := filepath.Join(tdir, "file")
+	old := time.Now().Add(-time.Hour)
+	writeFile := func(content string) os.FileInfo {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * racyThreshold)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+	d := digest.FromString("content")
+
+	// empty cache
+	c := LoadHashCache()
+	info := writeFile("content")
+	_, ok := c.Get(path, info)
+	assert.False(t, ok)
+
+	// cached digests survive save and load
+	c.Put(path, info, d)
+	assert.NoError(t, c.Save())
+	c = LoadHashCache()
+	cached, ok := c.Get(path, info)
+	assert.True(t, ok)
+	assert.Equal(t, d, cached)
+
+	// changed file is a miss
+	changed := writeFile("changed content")
+	_, ok = c.Get(path, changed)
+	assert.False(t, ok)
+
+	// in-place edit preserving size and mtime is a miss, by ctime
+	info = writeFile("content")
+	c.Put(path, info, d)
+	edited := writeFile("CONTENT")
+	assert.Equal(t, info.ModTime(), edited.ModTime())
+	_, ok = c.Get(path, edited)
+	assert.False(t, ok)
+
+	// recently modified files are not cached
+	if err := os.Chtimes(path, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	recent, _ := os.Stat(path)
+	c.Put(path, recent, d)
+	_, ok = c.Get(path, recent)
+	assert.False(t, ok)
+
+	// prune removes changed and missing files only
+	other := filepath.Join(tdir, "other")
+	c.Put(other, info, d)
+	assert.Equal(t, 2, c.Prune())
+	assert.Equal(t, 0, c.Len())
+
+	info = writeFile("content")
+	c.Put(path, info, d)
+	assert.Equal(t, 0, c.Prune())
+	assert.Equal(t, 1, c.Clear())
+}
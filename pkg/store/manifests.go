@@ -11,6 +11,8 @@ package store
 import (
 	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"github.com/vchain-us/vcn/pkg/bundle"
@@ -45,3 +47,22 @@ func ReadManifest(kind string, target string) (*bundle.Manifest, error) {
 	}
 	return bundle.ReadManifest(path)
 }
+
+// FindManifest returns the manifest stored in the working directory whose digest's encoded value is hash,
+// regardless of its kind and target.
+func FindManifest(hash string) (*bundle.Manifest, error) {
+	files, err := ioutil.ReadDir(filepath.Join(dir, defaultManifestsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, f := range files {
+		m, err := bundle.ReadManifest(filepath.Join(dir, defaultManifestsDir, f.Name()))
+		if err != nil {
+			continue // skip bad manifests
+		}
+		if d, err := m.Digest(); err == nil && d.Encoded() == hash {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no manifest found for %s", hash)
+}
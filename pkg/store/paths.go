@@ -70,3 +70,9 @@ func SetConfigFile(filepath string) {
 func CurrentConfigFilePath() string {
 	return dir
 }
+
+// PluginsDir returns the directory where external extractors can be installed (e.g. /tmp/.vcn/plugins).
+// The directory is not created.
+func PluginsDir() string {
+	return filepath.Join(dir, defaultPluginsDir)
+}
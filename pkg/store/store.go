@@ -28,3 +28,9 @@ const defaultSecretFile = "secret.json"
 const defaultAlertsDir = "alerts"
 
 const defaultManifestsDir = "manifests"
+
+const defaultCacheDir = "cache"
+
+const hashCacheFilename = "hashes.json"
+
+const defaultPluginsDir = "plugins"
@@ -11,21 +11,53 @@ package uri
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
 // URI represents the canonical identification of an artifact
 type URI struct {
-	Scheme string // Scheme identifies a kind of artifacts
-	Opaque string // Rest of encoded data
+	Scheme   string // Scheme identifies a kind of artifacts
+	Opaque   string // Rest of encoded data
+	RawQuery string // Encoded query values, without '?'
+}
+
+var queryKeyRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+var queryableSchemes = map[string]bool{}
+
+// RegisterQueryScheme declares that URIs of the given schemes can carry a query (i.e. "<scheme>://<opaque>?<query>").
+// URIs of other schemes never have a query, so '?' is always part of their opaque part (e.g. a file path).
+// It is meant to be called by the packages of the extractors accepting options by the query, when initialized.
+func RegisterQueryScheme(schemes ...string) {
+	for _, scheme := range schemes {
+		queryableSchemes[scheme] = true
+	}
 }
 
 // String implements the stringer interface
 func (u *URI) String() string {
-	if u.Scheme != "" {
-		return fmt.Sprintf("%s:%s", u.Scheme, u.Opaque)
+	if u.Scheme == "" {
+		return u.Opaque
+	}
+	opaque := u.Opaque
+	if u.RawQuery != "" {
+		// the query goes before the fragment, if any
+		fragment := ""
+		if i := strings.LastIndex(opaque, "#"); i >= 0 {
+			opaque, fragment = opaque[:i], opaque[i:]
+		}
+		opaque += "?" + u.RawQuery + fragment
 	}
-	return u.Opaque
+	return fmt.Sprintf("%s:%s", u.Scheme, opaque)
+}
+
+// Query parses RawQuery and returns the corresponding values.
+// Malformed pairs are silently discarded.
+func (u *URI) Query() url.Values {
+	v, _ := url.ParseQuery(u.RawQuery)
+	return v
 }
 
 // Parse converts a rawURI string into an URI structure
@@ -40,14 +72,49 @@ func Parse(rawURI string) (*URI, error) {
 
 	}
 	if l == 2 {
+		opaque, rawQuery := parts[1], ""
+		if queryableSchemes[parts[0]] {
+			opaque, rawQuery = splitQuery(parts[1])
+		}
 		return &URI{
-			Scheme: parts[0],
-			Opaque: "//" + parts[1],
+			Scheme:   parts[0],
+			Opaque:   "//" + opaque,
+			RawQuery: rawQuery,
 		}, nil
 	}
 	return nil, fmt.Errorf("invalid URI: %s", rawURI)
 }
 
+// splitQuery splits s (i.e. "opaque[?query][#fragment]") into the opaque part, which retains the fragment,
+// and the raw query. Since '?' is allowed within the opaque part (e.g. glob patterns), what follows the
+// last '?' is considered a query only when it consists of key=value pairs.
+func splitQuery(s string) (opaque string, rawQuery string) {
+	fragment := ""
+	if i := strings.LastIndex(s, "#"); i >= 0 {
+		s, fragment = s[:i], s[i:]
+	}
+	if i := strings.LastIndex(s, "?"); i >= 0 && isQuery(s[i+1:]) {
+		return s[:i] + fragment, s[i+1:]
+	}
+	return s + fragment, ""
+}
+
+func isQuery(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, pair := range strings.Split(s, "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || !queryKeyRe.MatchString(kv[0]) {
+			return false
+		}
+		if _, err := url.QueryUnescape(kv[1]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 // MarshalJSON implements the json.Marshaller interface
 func (u URI) MarshalJSON() ([]byte, error) {
 	return json.Marshal(u.String())
@@ -63,7 +130,6 @@ func (u *URI) UnmarshalJSON(input []byte) error {
 	if err != nil {
 		return err
 	}
-	u.Scheme = pu.Scheme
-	u.Opaque = pu.Opaque
+	*u = *pu
 	return nil
 }
@@ -9,6 +9,7 @@
 package uri
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,48 @@ func TestURI(t *testing.T) {
 	assert.Equal(t, URI{Scheme: "", Opaque: "file.txt"}, *u)
 	assert.Equal(t, "file.txt", u.String())
 }
+
+func TestURIQuery(t *testing.T) {
+	RegisterQueryScheme("docker", "dir", "git")
+
+	u, err := Parse("docker://alpine?platform=linux/arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, URI{Scheme: "docker", Opaque: "//alpine", RawQuery: "platform=linux/arm64"}, *u)
+	assert.Equal(t, "linux/arm64", u.Query().Get("platform"))
+	assert.Equal(t, "docker://alpine?platform=linux/arm64", u.String())
+
+	u, err = Parse("dir://src?ignore=*.log&ignore=tmp%2F")
+	assert.NoError(t, err)
+	assert.Equal(t, "//src", u.Opaque)
+	assert.Equal(t, []string{"*.log", "tmp/"}, u.Query()["ignore"])
+	assert.Equal(t, "dir://src?ignore=*.log&ignore=tmp%2F", u.String())
+
+	// the query goes before the fragment, which is retained by the opaque part
+	u, err = Parse("git://repo?tree=true#range=v1..v2")
+	assert.NoError(t, err)
+	assert.Equal(t, URI{Scheme: "git", Opaque: "//repo#range=v1..v2", RawQuery: "tree=true"}, *u)
+	assert.Equal(t, "git://repo?tree=true#range=v1..v2", u.String())
+
+	// not a query
+	for _, raw := range []string{"dir://file?.txt", "dir://what?", "dir://a?b=c=d&e"} {
+		u, err = Parse(raw)
+		assert.NoError(t, err)
+		assert.Empty(t, u.RawQuery, raw)
+		assert.Empty(t, u.Query(), raw)
+		assert.Equal(t, raw, u.String())
+	}
+
+	// schemes not supporting a query
+	for _, raw := range []string{"file://dl/report?id=3.pdf", "archive://a?b=c"} {
+		u, err = Parse(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, "//"+raw[strings.Index(raw, "://")+3:], u.Opaque, raw)
+		assert.Empty(t, u.RawQuery, raw)
+		assert.Equal(t, raw, u.String())
+	}
+
+	// no scheme, no query
+	u, err = Parse("file?a=b")
+	assert.NoError(t, err)
+	assert.Equal(t, URI{Opaque: "file?a=b"}, *u)
+}